@@ -1,9 +1,11 @@
 package artifact
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/verystar/jenkins-client/pkg/core"
 	"github.com/verystar/jenkins-client/pkg/job"
@@ -23,8 +25,18 @@ type Client struct {
 	core.JenkinsCore
 }
 
+// NewClient creates a Client which shares the HTTP client with the JenkinsCore
+func NewClient(jenkins *core.JenkinsCore) *Client {
+	return &Client{JenkinsCore: jenkins.Clone()}
+}
+
 // List get the list of artifacts from a build
 func (q *Client) List(jobName string, buildID int) (artifacts []Artifact, err error) {
+	return q.ListContext(context.Background(), jobName, buildID)
+}
+
+// ListContext is the same as List but accepts a context
+func (q *Client) ListContext(ctx context.Context, jobName string, buildID int) (artifacts []Artifact, err error) {
 	path := job.ParseJobPath(jobName)
 	var api string
 	if buildID < 1 {
@@ -32,25 +44,37 @@ func (q *Client) List(jobName string, buildID int) (artifacts []Artifact, err er
 	} else {
 		api = fmt.Sprintf("%s/%d/wfapi/artifacts", path, buildID)
 	}
-	err = q.RequestWithData(http.MethodGet, api, nil, nil, 200, &artifacts)
+	err = q.RequestWithDataContext(ctx, http.MethodGet, api, nil, nil, 200, &artifacts)
 	return
 }
 
 // GetArtifact download artifact using stream
 func (q *Client) GetArtifact(projectName, pipelineName string, buildID int, filename string) (io.ReadCloser, error) {
-	return q.GetArtifactFromMultiBranchPipeline(projectName, pipelineName, false, "", buildID, filename)
+	return q.GetArtifactContext(context.Background(), projectName, pipelineName, buildID, filename)
+}
+
+// GetArtifactContext is the same as GetArtifact but accepts a context
+func (q *Client) GetArtifactContext(ctx context.Context, projectName, pipelineName string, buildID int, filename string) (io.ReadCloser, error) {
+	return q.GetArtifactFromMultiBranchPipelineContext(ctx, projectName, pipelineName, false, "", buildID, filename)
 }
 
 // GetArtifactFromMultiBranchPipeline download multi pipeline artifact using stream
 func (q *Client) GetArtifactFromMultiBranchPipeline(projectName, pipelineName string, isMultiBranch bool, branchName string, buildID int, filename string) (io.ReadCloser, error) {
+	return q.GetArtifactFromMultiBranchPipelineContext(context.Background(), projectName, pipelineName, isMultiBranch, branchName, buildID, filename)
+}
+
+// GetArtifactFromMultiBranchPipelineContext is the same as GetArtifactFromMultiBranchPipeline but accepts a context
+func (q *Client) GetArtifactFromMultiBranchPipelineContext(ctx context.Context, projectName, pipelineName string, isMultiBranch bool, branchName string, buildID int, filename string) (io.ReadCloser, error) {
 	artifactURL := generateArtifactURL(projectName, pipelineName, isMultiBranch, branchName, buildID, filename)
-	resp, err := q.RequestWithResponse(http.MethodGet, artifactURL, nil, nil)
+	resp, err := q.RequestWithResponseContext(ctx, http.MethodGet, artifactURL, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get artifact. the HTTP status code is %d", resp.StatusCode)
+		data, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return nil, core.NewAPIError(resp, data)
 	}
 
 	return resp.Body, nil
@@ -58,8 +82,15 @@ func (q *Client) GetArtifactFromMultiBranchPipeline(projectName, pipelineName st
 
 // generateArtifactURL generate artifactURL by pipelineType
 func generateArtifactURL(projectName, pipelineName string, isMultiBranch bool, branchName string, buildID int, filename string) string {
+	path := core.NewItemPath(projectName, pipelineName)
 	if isMultiBranch {
-		return fmt.Sprintf("/job/%s/job/%s/job/%s/%d/artifact/%s", projectName, pipelineName, branchName, buildID, filename)
+		path = path.Branch(branchName)
+	}
+
+	// the filename is a relative path in the workspace
+	segments := strings.Split(filename, "/")
+	for i, segment := range segments {
+		segments[i] = core.EscapePathSegment(segment)
 	}
-	return fmt.Sprintf("/job/%s/job/%s/%d/artifact/%s", projectName, pipelineName, buildID, filename)
+	return fmt.Sprintf("%s/artifact/%s", path.Run(buildID), strings.Join(segments, "/"))
 }
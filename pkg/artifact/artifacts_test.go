@@ -129,6 +129,11 @@ var _ = Describe("artifacts test", func() {
 			url := generateArtifactURL(projectName, pipelineName, isMultiBranch, branchName, buildID, filename)
 			Expect(url).To(Equal(want))
 		})
+
+		It("should escape the branch and the filename", func() {
+			url := generateArtifactURL("my project", "pipeline", true, "feature/foo", 1, "target/a #1.jar")
+			Expect(url).To(Equal("/job/my%20project/job/pipeline/job/feature%252Ffoo/1/artifact/target/a%20%231.jar"))
+		})
 	})
 
 	Context("GetArtifactFromMultiBranchPipeline", func() {
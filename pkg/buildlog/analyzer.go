@@ -0,0 +1,253 @@
+package buildlog
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/verystar/jenkins-client/pkg/job"
+)
+
+// DefaultExcerptLines is the count of the lines in the excerpt if there is no one
+const DefaultExcerptLines = 20
+
+// maxLineSize is the max size of a line, the longer lines are truncated
+const maxLineSize = 1024 * 1024
+
+// EventType is the type of the events in a log
+type EventType string
+
+const (
+	// EventStageStart means a Pipeline stage starts
+	EventStageStart EventType = "stage-start"
+	// EventStageEnd means a Pipeline stage ends
+	EventStageEnd EventType = "stage-end"
+	// EventError means a line matches one of the rules
+	EventError EventType = "error"
+	// EventResult means the build finished with a result
+	EventResult EventType = "result"
+)
+
+// Event is an event in a log
+type Event struct {
+	Type EventType
+	// Line is the line number which starts from 1
+	Line int
+	// Text is the line without the timestamp
+	Text string
+	// Stage is the name of the stage for the stage events, or the current stage for the others
+	Stage string
+	// Rule is the name of the matched rule of the error events
+	Rule string
+	// Timestamp is from the timestamper plugin, it is zero if there is no timestamp.
+	// Only the time of the day is known for the timestamps such as 10:20:30
+	Timestamp time.Time
+}
+
+// Stage is a Pipeline stage in a log
+type Stage struct {
+	Name      string
+	StartLine int
+	EndLine   int
+	Start     time.Time
+	End       time.Time
+}
+
+// Summary is the result of analyzing a log
+type Summary struct {
+	Events []Event
+	Stages []Stage
+	// Result is the result of the build, such as SUCCESS or FAILURE, it is empty if the log is not finished
+	Result string
+	// Excerpt is the last lines until the first error, including the error line,
+	// or the last lines of an unsuccessful build without errors.
+	// The Pipeline step lines are excluded.
+	Excerpt []string
+	// Lines is the count of the lines
+	Lines int
+}
+
+// Errors returns the error events
+func (s *Summary) Errors() (events []Event) {
+	for _, event := range s.Events {
+		if event.Type == EventError {
+			events = append(events, event)
+		}
+	}
+	return
+}
+
+// Analyzer extracts the events from the console log of a freestyle build, a Pipeline build or a BlueOcean step.
+// The stages of the parallel branches might be mixed up since their lines are interleaved.
+type Analyzer struct {
+	// Rules are DefaultRules if it is nil
+	Rules []Rule
+	// ExcerptLines is DefaultExcerptLines if it is not positive
+	ExcerptLines int
+	// OnEvent is called once an event is found
+	OnEvent func(event Event)
+}
+
+var (
+	consoleNotePattern = regexp.MustCompile("\x1b\\[8mha:[^\x1b]*\x1b\\[0m")
+	colorPattern       = regexp.MustCompile("\x1b\\[[0-9;]*m")
+	timestampPattern   = regexp.MustCompile(`^\[?(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?|\d{2}:\d{2}:\d{2})]?\s`)
+	stageNamePattern   = regexp.MustCompile(`^\[Pipeline] \{ \((.*)\)$`)
+)
+
+// Analyze reads the log until the end, then returns the summary
+func (a *Analyzer) Analyze(reader io.Reader) (summary *Summary, err error) {
+	state := a.newState()
+	buffered := bufio.NewReader(reader)
+	var line []byte
+	for {
+		var fragment []byte
+		var isPrefix bool
+		if fragment, isPrefix, err = buffered.ReadLine(); err != nil {
+			break
+		}
+		if len(line) < maxLineSize {
+			line = append(line, fragment[:min(len(fragment), maxLineSize-len(line))]...)
+		}
+		if !isPrefix {
+			state.line(string(line))
+			line = line[:0]
+		}
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	summary = state.finish()
+	return
+}
+
+// AnalyzeBuild follows the console log of a build until it finishes, then returns the summary
+func (a *Analyzer) AnalyzeBuild(ctx context.Context, client *job.Client, jobName string, build int) (*Summary, error) {
+	reader := client.LogReader(ctx, jobName, build, job.LogOptions{})
+	defer func() {
+		_ = reader.Close()
+	}()
+	return a.Analyze(reader)
+}
+
+// AnalyzeStep follows the log of a BlueOcean node or step until it finishes, then returns the summary
+func (a *Analyzer) AnalyzeStep(ctx context.Context, client *job.BlueOceanClient, option job.GetLogOption) (*Summary, error) {
+	reader := client.LogReader(ctx, option, job.LogOptions{})
+	defer func() {
+		_ = reader.Close()
+	}()
+	return a.Analyze(reader)
+}
+
+type analyzeState struct {
+	Analyzer
+	summary      *Summary
+	stages       []int
+	pendingStage bool
+	recent       []string
+}
+
+func (a *Analyzer) newState() *analyzeState {
+	state := &analyzeState{Analyzer: *a, summary: &Summary{}}
+	if state.Rules == nil {
+		state.Rules = DefaultRules
+	}
+	if state.ExcerptLines <= 0 {
+		state.ExcerptLines = DefaultExcerptLines
+	}
+	return state
+}
+
+func (s *analyzeState) line(raw string) {
+	s.summary.Lines++
+	text := colorPattern.ReplaceAllString(consoleNotePattern.ReplaceAllString(strings.TrimSuffix(raw, "\r"), ""), "")
+	event := Event{Line: s.summary.Lines, Stage: s.currentStage()}
+	if match := timestampPattern.FindStringSubmatch(text); match != nil {
+		event.Timestamp = parseTimestamp(match[1])
+		text = text[len(match[0]):]
+	}
+	event.Text = text
+
+	switch {
+	case text == "[Pipeline] stage":
+		s.pendingStage = true
+		return
+	case s.pendingStage && stageNamePattern.MatchString(text):
+		s.pendingStage = false
+		event.Type, event.Stage = EventStageStart, stageNamePattern.FindStringSubmatch(text)[1]
+		s.stages = append(s.stages, len(s.summary.Stages))
+		s.summary.Stages = append(s.summary.Stages, Stage{Name: event.Stage, StartLine: event.Line, Start: event.Timestamp})
+		s.emit(event)
+		return
+	case text == "[Pipeline] // stage" && len(s.stages) > 0:
+		stage := &s.summary.Stages[s.stages[len(s.stages)-1]]
+		s.stages = s.stages[:len(s.stages)-1]
+		stage.EndLine, stage.End = event.Line, event.Timestamp
+		event.Type, event.Stage = EventStageEnd, stage.Name
+		s.emit(event)
+		return
+	case strings.HasPrefix(text, "[Pipeline] "):
+		s.pendingStage = false
+		return
+	}
+
+	s.remember(text)
+	if result := strings.TrimPrefix(text, "Finished: "); result != text {
+		s.summary.Result = result
+		event.Type = EventResult
+		s.emit(event)
+		return
+	}
+	for _, rule := range s.Rules {
+		if rule.Pattern.MatchString(text) {
+			event.Type, event.Rule = EventError, rule.Name
+			if s.summary.Excerpt == nil {
+				s.summary.Excerpt = append([]string{}, s.recent...)
+			}
+			s.emit(event)
+			return
+		}
+	}
+}
+
+func (s *analyzeState) currentStage() string {
+	if len(s.stages) == 0 {
+		return ""
+	}
+	return s.summary.Stages[s.stages[len(s.stages)-1]].Name
+}
+
+// remember keeps the recent lines for the excerpt
+func (s *analyzeState) remember(text string) {
+	if len(s.recent) == s.ExcerptLines {
+		s.recent = append(s.recent[:0], s.recent[1:]...)
+	}
+	s.recent = append(s.recent, text)
+}
+
+func (s *analyzeState) emit(event Event) {
+	s.summary.Events = append(s.summary.Events, event)
+	if s.OnEvent != nil {
+		s.OnEvent(event)
+	}
+}
+
+func (s *analyzeState) finish() *Summary {
+	if s.summary.Excerpt == nil && s.summary.Result != "" && s.summary.Result != "SUCCESS" {
+		s.summary.Excerpt = s.recent
+	}
+	return s.summary
+}
+
+func parseTimestamp(text string) (timestamp time.Time) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", time.TimeOnly} {
+		var err error
+		if timestamp, err = time.Parse(layout, text); err == nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,168 @@
+package buildlog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/core"
+	"github.com/verystar/jenkins-client/pkg/job"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+const pipelineLog = `Started by user admin
+[Pipeline] Start of Pipeline
+[Pipeline] node
+[Pipeline] {
+[Pipeline] stage
+[Pipeline] { (Checkout)
+[2024-01-02T10:00:00.000Z] Cloning the repository
+[Pipeline] }
+[Pipeline] // stage
+[Pipeline] stage
+[Pipeline] { (Test)
+[2024-01-02T10:00:05.000Z] + make test
+[2024-01-02T10:00:06.000Z] ` + "\x1b[31m" + `--- FAIL: TestSomething` + "\x1b[0m" + `
+[2024-01-02T10:00:07.000Z] make: *** [test] Error 1
+[Pipeline] }
+[Pipeline] // stage
+[Pipeline] }
+[Pipeline] // node
+[Pipeline] End of Pipeline
+ERROR: script returned exit code 2
+Finished: FAILURE
+`
+
+var _ = Describe("analyzer test", func() {
+	It("analyze a Pipeline log", func() {
+		var events []Event
+		analyzer := &Analyzer{ExcerptLines: 3, OnEvent: func(event Event) {
+			events = append(events, event)
+		}}
+
+		summary, err := analyzer.Analyze(strings.NewReader(pipelineLog))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.Lines).To(Equal(21))
+		Expect(summary.Result).To(Equal("FAILURE"))
+		Expect(summary.Events).To(Equal(events))
+
+		Expect(summary.Stages).To(HaveLen(2))
+		Expect(summary.Stages[0]).To(Equal(Stage{Name: "Checkout", StartLine: 6, EndLine: 9}))
+		Expect(summary.Stages[1].Name).To(Equal("Test"))
+		Expect(summary.Stages[1].StartLine).To(Equal(11))
+		Expect(summary.Stages[1].EndLine).To(Equal(16))
+
+		errs := summary.Errors()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Text).To(Equal("ERROR: script returned exit code 2"))
+		Expect(errs[0].Rule).To(Equal("jenkins"))
+		Expect(errs[0].Line).To(Equal(20))
+		Expect(summary.Excerpt).To(Equal([]string{
+			"--- FAIL: TestSomething",
+			"make: *** [test] Error 1",
+			"ERROR: script returned exit code 2",
+		}))
+	})
+
+	It("parse the timestamps", func() {
+		var events []Event
+		analyzer := &Analyzer{
+			Rules: []Rule{{Name: "fail", Pattern: DefaultRules[0].Pattern}},
+			OnEvent: func(event Event) {
+				events = append(events, event)
+			},
+		}
+		rule, err := NewRule("make", `^make: \*\*\*`)
+		Expect(err).NotTo(HaveOccurred())
+		analyzer.Rules = append(analyzer.Rules, rule)
+
+		summary, err := analyzer.Analyze(strings.NewReader(pipelineLog))
+		Expect(err).NotTo(HaveOccurred())
+		errs := summary.Errors()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Rule).To(Equal("make"))
+		Expect(errs[0].Stage).To(Equal("Test"))
+		Expect(errs[0].Timestamp).To(Equal(time.Date(2024, 1, 2, 10, 0, 7, 0, time.UTC)))
+
+		summary, err = analyzer.Analyze(strings.NewReader("10:20:30 ERROR: failed\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.Events[0].Text).To(Equal("ERROR: failed"))
+		Expect(summary.Events[0].Timestamp.Format(time.TimeOnly)).To(Equal("10:20:30"))
+	})
+
+	It("the last lines of a failed freestyle build", func() {
+		summary, err := (&Analyzer{ExcerptLines: 2}).Analyze(strings.NewReader("a\nb\nc\nFinished: UNSTABLE\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.Errors()).To(BeEmpty())
+		Expect(summary.Excerpt).To(Equal([]string{"c", "Finished: UNSTABLE"}))
+	})
+
+	It("truncate the long lines", func() {
+		long := strings.Repeat("x", maxLineSize+10)
+		summary, err := (&Analyzer{}).Analyze(strings.NewReader("a\n" + long + "\nERROR: failed\nFinished: FAILURE"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.Lines).To(Equal(4))
+		Expect(summary.Result).To(Equal("FAILURE"))
+		Expect(summary.Errors()).To(HaveLen(1))
+		Expect(summary.Excerpt).To(Equal([]string{"a", long[:maxLineSize], "ERROR: failed"}))
+	})
+
+	It("no excerpt for a successful build", func() {
+		summary, err := (&Analyzer{}).Analyze(strings.NewReader("Caused by: java.lang.IllegalStateException: x\nFinished: SUCCESS\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.Errors()[0].Rule).To(Equal("exception"))
+		Expect(summary.Result).To(Equal("SUCCESS"))
+		Expect(summary.Excerpt).To(Equal([]string{"Caused by: java.lang.IllegalStateException: x"}))
+	})
+
+	It("analyze the log of a build", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		defer ctrl.Finish()
+		roundTripper := mhttp.NewMockRoundTripper(ctrl)
+		client := &job.Client{}
+		client.RoundTripper = roundTripper
+		client.URL = "http://localhost"
+
+		request, _ := http.NewRequest(http.MethodGet, "http://localhost/job/fake/3/logText/progressiveText?start=0", nil)
+		roundTripper.EXPECT().RoundTrip(core.NewRequestMatcher(request).WithQuery()).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Request:    request,
+			Header:     http.Header{"X-More-Data": {"false"}},
+			Body:       io.NopCloser(bytes.NewBufferString(pipelineLog)),
+		}, nil)
+
+		summary, err := (&Analyzer{}).AnalyzeBuild(context.Background(), client, "fake", 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.Result).To(Equal("FAILURE"))
+	})
+
+	It("analyze the log of a BlueOcean step", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		defer ctrl.Finish()
+		roundTripper := mhttp.NewMockRoundTripper(ctrl)
+		client := &job.BlueOceanClient{Organization: "jenkins"}
+		client.RoundTripper = roundTripper
+		client.URL = "http://localhost"
+
+		request, _ := http.NewRequest(http.MethodGet,
+			"http://localhost/blue/rest/organizations/jenkins/pipelines/fake/runs/3/nodes/6/steps/7/log/?start=0", nil)
+		roundTripper.EXPECT().RoundTrip(core.NewRequestMatcher(request).WithQuery()).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Request:    request,
+			Body:       io.NopCloser(bytes.NewBufferString("+ make test\nmake: *** [test] Error 1\nscript returned exit code 2\n")),
+		}, nil)
+
+		summary, err := (&Analyzer{}).AnalyzeStep(context.Background(), client, job.GetLogOption{
+			PipelineName: "fake", RunID: "3", NodeID: "6", StepID: "7",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.Errors()).To(HaveLen(1))
+		Expect(summary.Excerpt).To(HaveLen(3))
+	})
+})
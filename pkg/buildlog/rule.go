@@ -0,0 +1,26 @@
+package buildlog
+
+import "regexp"
+
+// Rule matches the error lines of a log
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// NewRule creates a Rule, the pattern is a regular expression
+func NewRule(name, pattern string) (rule Rule, err error) {
+	var reg *regexp.Regexp
+	if reg, err = regexp.Compile(pattern); err == nil {
+		rule = Rule{Name: name, Pattern: reg}
+	}
+	return
+}
+
+// DefaultRules are the rules of the common errors from Jenkins, shell scripts, Maven and Java
+var DefaultRules = []Rule{
+	{Name: "jenkins", Pattern: regexp.MustCompile(`^(ERROR|FATAL): `)},
+	{Name: "exit-code", Pattern: regexp.MustCompile(`exit code [1-9][0-9]*`)},
+	{Name: "maven", Pattern: regexp.MustCompile(`^\[ERROR\] `)},
+	{Name: "exception", Pattern: regexp.MustCompile(`^(Caused by: )?([a-zA-Z_$][\w$]*\.)+[\w$]*(Exception|Error)(: |$)`)},
+}
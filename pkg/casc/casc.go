@@ -1,6 +1,7 @@
 package casc
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 
@@ -12,9 +13,19 @@ type Manager struct {
 	core.JenkinsCore
 }
 
+// NewManager creates a Manager which shares the HTTP client with the JenkinsCore
+func NewManager(jenkins *core.JenkinsCore) *Manager {
+	return &Manager{JenkinsCore: jenkins.Clone()}
+}
+
 // Export exports the config of configuration-as-code
 func (c *Manager) Export() (config string, err error) {
-	request := core.NewRequest("/configuration-as-code/export", &c.JenkinsCore)
+	return c.ExportContext(context.Background())
+}
+
+// ExportContext is the same as Export but accepts a context
+func (c *Manager) ExportContext(ctx context.Context) (config string, err error) {
+	request := core.NewRequestWithContext(ctx, "/configuration-as-code/export", &c.JenkinsCore)
 	request.WithPostMethod()
 	if err = request.Do(); err == nil {
 		config = string(request.GetData())
@@ -24,7 +35,12 @@ func (c *Manager) Export() (config string, err error) {
 
 // Schema get the schema of configuration-as-code
 func (c *Manager) Schema() (schema string, err error) {
-	request := core.NewRequest("/configuration-as-code/schema", &c.JenkinsCore)
+	return c.SchemaContext(context.Background())
+}
+
+// SchemaContext is the same as Schema but accepts a context
+func (c *Manager) SchemaContext(ctx context.Context) (schema string, err error) {
+	request := core.NewRequestWithContext(ctx, "/configuration-as-code/schema", &c.JenkinsCore)
 	request.WithPostMethod()
 	if err = request.Do(); err == nil {
 		schema = string(request.GetData())
@@ -34,19 +50,29 @@ func (c *Manager) Schema() (schema string, err error) {
 
 // Reload reloads the config of configuration-as-code
 func (c *Manager) Reload() (err error) {
-	request := core.NewRequest("/configuration-as-code/reload", &c.JenkinsCore)
+	return c.ReloadContext(context.Background())
+}
+
+// ReloadContext is the same as Reload but accepts a context
+func (c *Manager) ReloadContext(ctx context.Context) (err error) {
+	request := core.NewRequestWithContext(ctx, "/configuration-as-code/reload", &c.JenkinsCore)
 	err = request.WithPostMethod().Do()
 	return
 }
 
 // Replace replaces the new source
 func (c *Manager) Replace(source string) (err error) {
+	return c.ReplaceContext(context.Background(), source)
+}
+
+// ReplaceContext is the same as Replace but accepts a context
+func (c *Manager) ReplaceContext(ctx context.Context, source string) (err error) {
 	formValue := make(url.Values)
 	formValue.Set("json", fmt.Sprintf(`{"newSource": "%s"}`, source))
 	formValue.Set("_.newSource", source)
 
 	// Jenkins does not have a standard API. This is a form submit, so the expected code is not 200
-	request := core.NewRequest("/configuration-as-code/replace", &c.JenkinsCore)
+	request := core.NewRequestWithContext(ctx, "/configuration-as-code/replace", &c.JenkinsCore)
 	request.WithPostMethod().AsFormRequest().WithValues(formValue).AcceptStatusCode(302)
 	err = request.Do()
 	if urlErr, ok := err.(*url.Error); ok && urlErr.Err.Error() == "302 response missing Location header" {
@@ -57,10 +83,15 @@ func (c *Manager) Replace(source string) (err error) {
 
 // CheckNewSource checks the new source of CasC
 func (c *Manager) CheckNewSource(source string) (err error) {
+	return c.CheckNewSourceContext(context.Background(), source)
+}
+
+// CheckNewSourceContext is the same as CheckNewSource but accepts a context
+func (c *Manager) CheckNewSourceContext(ctx context.Context, source string) (err error) {
 	formValue := make(url.Values)
 	formValue.Set("newSource", source)
 
-	request := core.NewRequest("/configuration-as-code/checkNewSource", &c.JenkinsCore)
+	request := core.NewRequestWithContext(ctx, "/configuration-as-code/checkNewSource", &c.JenkinsCore)
 	request.WithPostMethod().AsFormRequest().WithValues(formValue)
 	err = request.Do()
 	return
@@ -68,7 +99,12 @@ func (c *Manager) CheckNewSource(source string) (err error) {
 
 // Apply applies the config of configuration-as-code
 func (c *Manager) Apply() (err error) {
-	request := core.NewRequest("/configuration-as-code/apply", &c.JenkinsCore)
+	return c.ApplyContext(context.Background())
+}
+
+// ApplyContext is the same as Apply but accepts a context
+func (c *Manager) ApplyContext(ctx context.Context) (err error) {
+	request := core.NewRequestWithContext(ctx, "/configuration-as-code/apply", &c.JenkinsCore)
 	request.WithPostMethod()
 	err = request.Do()
 	return
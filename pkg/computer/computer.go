@@ -1,9 +1,9 @@
 package computer
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"runtime"
@@ -17,24 +17,42 @@ type Client struct {
 	core.JenkinsCore
 }
 
-// List get the computer list
+// NewClient creates a Client which shares the HTTP client with the JenkinsCore
+func NewClient(jenkins *core.JenkinsCore) *Client {
+	return &Client{JenkinsCore: jenkins.Clone()}
+}
+
+// List get the computer list, only the fields of List are fetched
 func (c *Client) List() (computers List, err error) {
-	err = c.RequestWithData(http.MethodGet, "/computer/api/json",
-		nil, nil, 200, &computers)
-	return
+	return c.ListContext(context.Background())
+}
+
+// ListContext is the same as List but accepts a context
+func (c *Client) ListContext(ctx context.Context) (computers List, err error) {
+	return core.GetAPI[List](ctx, &c.JenkinsCore, "/computer", core.APIOptions{})
 }
 
 // Launch starts up a agent
 func (c *Client) Launch(name string) (err error) {
+	return c.LaunchContext(context.Background(), name)
+}
+
+// LaunchContext is the same as Launch but accepts a context
+func (c *Client) LaunchContext(ctx context.Context, name string) (err error) {
 	api := fmt.Sprintf("/computer/%s/launchSlaveAgent", name)
-	_, err = c.RequestWithoutData(http.MethodPost, api, nil, nil, 200)
+	_, err = c.RequestWithoutDataContext(ctx, http.MethodPost, api, nil, nil, 200)
 	return
 }
 
 // Delete removes a agent from Jenkins
 func (c *Client) Delete(name string) (err error) {
+	return c.DeleteContext(context.Background(), name)
+}
+
+// DeleteContext is the same as Delete but accepts a context
+func (c *Client) DeleteContext(ctx context.Context, name string) (err error) {
 	api := fmt.Sprintf("/computer/%s/doDelete", name)
-	_, err = c.RequestWithoutData(http.MethodPost, api, nil, nil, 200)
+	_, err = c.RequestWithoutDataContext(ctx, http.MethodPost, api, nil, nil, 200)
 	return
 }
 
@@ -45,21 +63,19 @@ type agentJNLP struct {
 
 // GetSecret returns the secret of an agent
 func (c *Client) GetSecret(name string) (secret string, err error) {
+	return c.GetSecretContext(context.Background(), name)
+}
+
+// GetSecretContext is the same as GetSecret but accepts a context
+func (c *Client) GetSecretContext(ctx context.Context, name string) (secret string, err error) {
 	api := fmt.Sprintf("/computer/%s/slave-agent.jnlp", name)
-	var response *http.Response
-	if response, err = c.RequestWithResponse(http.MethodGet, api, nil, nil); err == nil {
-		if response.StatusCode == http.StatusOK {
-			var data []byte
-			if data, err = ioutil.ReadAll(response.Body); err == nil {
-				jnlp := &agentJNLP{}
-				if err = xml.Unmarshal(data, jnlp); err == nil {
-					secret = jnlp.AppArguments[0]
-				} else {
-					err = fmt.Errorf("invalid jnlp xml, error: %v", err)
-				}
-			}
+	request := core.NewRequestWithContext(ctx, api, &c.JenkinsCore)
+	if err = request.Do(); err == nil {
+		jnlp := &agentJNLP{}
+		if err = xml.Unmarshal(request.GetData(), jnlp); err == nil {
+			secret = jnlp.AppArguments[0]
 		} else {
-			err = fmt.Errorf("unexpected status code: %d", response.StatusCode)
+			err = fmt.Errorf("invalid jnlp xml, error: %v", err)
 		}
 	}
 	return
@@ -67,34 +83,35 @@ func (c *Client) GetSecret(name string) (secret string, err error) {
 
 // GetLog fetch the log a computer
 func (c *Client) GetLog(name string) (log string, err error) {
-	var response *http.Response
-	api := fmt.Sprintf("/computer/%s/logText/progressiveText", name)
-	if response, err = c.RequestWithResponse(http.MethodGet, api, nil, nil); err == nil {
-		statusCode := response.StatusCode
-		if statusCode != 200 {
-			err = fmt.Errorf("unexpected status code %d", statusCode)
-			return
-		}
+	return c.GetLogContext(context.Background(), name)
+}
 
-		var data []byte
-		if data, err = ioutil.ReadAll(response.Body); err == nil {
-			log = string(data)
-		}
+// GetLogContext is the same as GetLog but accepts a context
+func (c *Client) GetLogContext(ctx context.Context, name string) (log string, err error) {
+	api := fmt.Sprintf("/computer/%s/logText/progressiveText", name)
+	request := core.NewRequestWithContext(ctx, api, &c.JenkinsCore)
+	if err = request.Do(); err == nil {
+		log = string(request.GetData())
 	}
 	return
 }
 
 // Create creates a computer by name
 func (c *Client) Create(name string) (err error) {
+	return c.CreateContext(context.Background(), name)
+}
+
+// CreateContext is the same as Create but accepts a context
+func (c *Client) CreateContext(ctx context.Context, name string) (err error) {
 	formData := url.Values{
 		"name": {name},
 		"mode": {"hudson.slaves.DumbSlave"},
 	}
 	payload := strings.NewReader(formData.Encode())
-	if _, err = c.RequestWithoutData(http.MethodPost, "/computer/createItem",
+	if _, err = c.RequestWithoutDataContext(ctx, http.MethodPost, "/computer/createItem",
 		map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, payload, 200); err == nil {
 		payload = GetPayloadForCreateAgent(name)
-		_, err = c.RequestWithoutData(http.MethodPost, "/computer/doCreateItem",
+		_, err = c.RequestWithoutDataContext(ctx, http.MethodPost, "/computer/doCreateItem",
 			map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, payload, 200)
 	}
 	return
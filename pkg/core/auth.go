@@ -0,0 +1,167 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Authenticator authenticates the requests sent to Jenkins
+type Authenticator interface {
+	// Authenticate sets the credentials on the request, the JenkinsCore is the one which sends the request
+	Authenticate(j *JenkinsCore, request *http.Request) error
+}
+
+// TLSAuthenticator is an Authenticator which works on the TLS layer, such as the client certificates.
+// The transport is shared by the same pointer, or the values with the same content.
+type TLSAuthenticator interface {
+	Authenticator
+	// ConfigureTLS sets the credentials on the TLS config of the transport
+	ConfigureTLS(config *tls.Config)
+}
+
+// APITokenAuth authenticates the requests with the username and the API token of a Jenkins user
+type APITokenAuth struct {
+	UserName string
+	Token    string
+}
+
+// Authenticate sets the basic auth header
+func (a APITokenAuth) Authenticate(_ *JenkinsCore, request *http.Request) error {
+	if a.UserName != "" && a.Token != "" {
+		request.SetBasicAuth(a.UserName, a.Token)
+	}
+	return nil
+}
+
+// BearerTokenAuth authenticates the requests with a bearer token, such as an OIDC token for a reverse proxy
+type BearerTokenAuth struct {
+	// Token is a static token, it is ignored if there is a TokenSource
+	Token string
+	// TokenSource returns a fresh token for each request, it could be used to refresh the expired tokens
+	TokenSource func(ctx context.Context) (string, error)
+}
+
+// Authenticate sets the bearer token header
+func (a BearerTokenAuth) Authenticate(_ *JenkinsCore, request *http.Request) (err error) {
+	token := a.Token
+	if a.TokenSource != nil {
+		if token, err = a.TokenSource(request.Context()); err != nil {
+			return fmt.Errorf("failed to get the bearer token, error is %v", err)
+		}
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	return
+}
+
+// ClientCertificateAuth authenticates with the mutual TLS client certificates
+type ClientCertificateAuth struct {
+	Certificates []tls.Certificate
+}
+
+// NewClientCertificateAuth loads the client certificate from a pair of PEM encoded files
+func NewClientCertificateAuth(certFile, keyFile string) (auth *ClientCertificateAuth, err error) {
+	var cert tls.Certificate
+	if cert, err = tls.LoadX509KeyPair(certFile, keyFile); err == nil {
+		auth = &ClientCertificateAuth{Certificates: []tls.Certificate{cert}}
+	}
+	return
+}
+
+// Authenticate does nothing, the certificates take effect on the TLS layer
+func (a *ClientCertificateAuth) Authenticate(_ *JenkinsCore, _ *http.Request) error {
+	return nil
+}
+
+// ConfigureTLS adds the client certificates to the TLS config
+func (a *ClientCertificateAuth) ConfigureTLS(config *tls.Config) {
+	config.Certificates = append(config.Certificates, a.Certificates...)
+}
+
+// FormLoginAuth logs in via the login form of Jenkins, then the requests are authenticated by the session cookie.
+// It logs in again once the session changed, e.g. the session is expired.
+type FormLoginAuth struct {
+	UserName string
+	Password string
+}
+
+// loginSessions keeps the sessions which the FormLoginAuth logged in with.
+// It belongs to a clientCache, so the sessions go away together with the cookie jar.
+type loginSessions struct {
+	lock     sync.Mutex
+	sessions map[*FormLoginAuth]string
+}
+
+// Authenticate logs in if there is no valid session
+func (a *FormLoginAuth) Authenticate(j *JenkinsCore, request *http.Request) (err error) {
+	cache := j.getClientCache()
+	logins := &cache.logins
+
+	logins.lock.Lock()
+	defer logins.lock.Unlock()
+	if session := cache.session(j.URL); session != "" && logins.sessions[a] == session {
+		return
+	}
+
+	if err = a.login(j, request); err != nil {
+		return
+	}
+	session := cache.session(j.URL)
+	if session == "" {
+		// it would log in again for every request without the session cookie
+		return fmt.Errorf("no session cookie after logging in Jenkins as %s", a.UserName)
+	}
+	if logins.sessions == nil {
+		logins.sessions = map[*FormLoginAuth]string{}
+	}
+	logins.sessions[a] = session
+	return
+}
+
+func (a *FormLoginAuth) login(j *JenkinsCore, request *http.Request) (err error) {
+	var loginURL string
+	if loginURL, err = url.JoinPath(j.URL, "/j_spring_security_check"); err != nil {
+		return
+	}
+
+	formData := url.Values{
+		"j_username": {a.UserName},
+		"j_password": {a.Password},
+		"from":       {"/"},
+		"Submit":     {"Sign in"},
+	}
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(request.Context(), http.MethodPost, loginURL,
+		strings.NewReader(formData.Encode())); err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	j.ProxyHandle(req)
+
+	// do not follow the redirection, it tells whether the login is successful
+	client := *j.GetClient()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	var response *http.Response
+	if response, err = client.Do(req); err != nil {
+		return
+	}
+	discardResponse(response)
+
+	location := response.Header.Get("Location")
+	switch {
+	case response.StatusCode >= 400:
+		err = NewAPIError(response, nil)
+	case strings.Contains(location, "loginError"):
+		err = fmt.Errorf("failed to login Jenkins as %s", a.UserName)
+	}
+	return
+}
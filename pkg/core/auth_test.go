@@ -0,0 +1,182 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("authenticator test", func() {
+	var (
+		ctrl         *gomock.Controller
+		jenkinsCore  JenkinsCore
+		roundTripper *mhttp.MockRoundTripper
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		jenkinsCore = JenkinsCore{}
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		jenkinsCore.RoundTripper = roundTripper
+		jenkinsCore.URL = "http://localhost"
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	okResponse := func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Request:    req,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+	}
+
+	It("the authenticator takes the place of the username and token", func() {
+		jenkinsCore.UserName = "admin"
+		jenkinsCore.Token = "token"
+		jenkinsCore.Authenticator = APITokenAuth{UserName: "fake", Token: "fake-token"}
+
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			user, token, ok := req.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(user).To(Equal("fake"))
+			Expect(token).To(Equal("fake-token"))
+			return okResponse(req), nil
+		})
+
+		err := NewRequest("/fake", &jenkinsCore).Do()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("BearerTokenAuth", func() {
+		It("with a static token", func() {
+			jenkinsCore.Authenticator = BearerTokenAuth{Token: "fake"}
+
+			roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				Expect(req.Header.Get("Authorization")).To(Equal("Bearer fake"))
+				return okResponse(req), nil
+			})
+
+			err := NewRequest("/fake", &jenkinsCore).Do()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("get a fresh token for each request", func() {
+			count := 0
+			jenkinsCore.Authenticator = BearerTokenAuth{TokenSource: func(ctx context.Context) (string, error) {
+				count++
+				return strings.Repeat("a", count), nil
+			}}
+
+			var tokens []string
+			roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				tokens = append(tokens, req.Header.Get("Authorization"))
+				return okResponse(req), nil
+			}).Times(2)
+
+			Expect(NewRequest("/fake", &jenkinsCore).Do()).To(Succeed())
+			Expect(NewRequest("/fake", &jenkinsCore).Do()).To(Succeed())
+			Expect(tokens).To(Equal([]string{"Bearer a", "Bearer aa"}))
+		})
+
+		It("failed to get the token", func() {
+			jenkinsCore.Authenticator = BearerTokenAuth{TokenSource: func(ctx context.Context) (string, error) {
+				return "", errors.New("fake")
+			}}
+
+			err := NewRequest("/fake", &jenkinsCore).Do()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("FormLoginAuth", func() {
+		var (
+			loginCount int
+			loginError bool
+			noSession  bool
+		)
+
+		BeforeEach(func() {
+			loginCount = 0
+			loginError = false
+			noSession = false
+			jenkinsCore.Authenticator = &FormLoginAuth{UserName: "admin", Password: "password"}
+
+			roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				response := okResponse(req)
+				if req.URL.Path != "/j_spring_security_check" {
+					Expect(req.Header.Get("Cookie")).To(ContainSubstring("JSESSIONID.fake="))
+					return response, nil
+				}
+
+				loginCount++
+				Expect(req.ParseForm()).To(Succeed())
+				Expect(req.PostForm.Get("j_username")).To(Equal("admin"))
+				Expect(req.PostForm.Get("j_password")).To(Equal("password"))
+
+				response.StatusCode = http.StatusFound
+				if loginError {
+					response.Header.Set("Location", "/loginError")
+				} else {
+					response.Header.Set("Location", "/")
+					if !noSession {
+						response.Header.Set("Set-Cookie", "JSESSIONID.fake=session; Path=/")
+					}
+				}
+				return response, nil
+			}).AnyTimes()
+		})
+
+		It("login once and reuse the session", func() {
+			Expect(NewRequest("/fake", &jenkinsCore).Do()).To(Succeed())
+			Expect(NewRequest("/fake", &jenkinsCore).Do()).To(Succeed())
+			Expect(loginCount).To(Equal(1))
+		})
+
+		It("fail without the session cookie", func() {
+			noSession = true
+			Expect(NewRequest("/fake", &jenkinsCore).Do()).To(MatchError(ContainSubstring("no session cookie")))
+			Expect(loginCount).To(Equal(1))
+		})
+
+		It("keep the sessions in the cache of the JenkinsCore", func() {
+			Expect(NewRequest("/fake", &jenkinsCore).Do()).To(Succeed())
+			Expect(jenkinsCore.getClientCache().logins.sessions).To(HaveLen(1))
+
+			// a new JenkinsCore has its own cookies and sessions
+			another := JenkinsCore{URL: jenkinsCore.URL, RoundTripper: roundTripper, Authenticator: jenkinsCore.Authenticator}
+			Expect(NewRequest("/fake", &another).Do()).To(Succeed())
+			Expect(loginCount).To(Equal(2))
+			Expect(jenkinsCore.getClientCache().logins.sessions).To(HaveLen(1))
+		})
+
+		It("failed to login", func() {
+			loginError = true
+			err := NewRequest("/fake", &jenkinsCore).Do()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to login"))
+		})
+	})
+
+	It("configure the TLS with client certificates", func() {
+		auth := &ClientCertificateAuth{Certificates: []tls.Certificate{{}}}
+		jenkinsCore.Authenticator = auth
+
+		key, tlsAuth := jenkinsCore.transportKey()
+		Expect(tlsAuth).To(Equal(auth))
+		tr, err := newTransport(key, tlsAuth)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tr.TLSClientConfig.Certificates).To(HaveLen(1))
+	})
+})
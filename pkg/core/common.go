@@ -1,13 +1,13 @@
 package core
 
 import (
-	"crypto/tls"
+	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"log/slog"
 	"net/http"
 	"net/url"
@@ -28,6 +28,8 @@ func SetLanguage(lan string) {
 // JenkinsCore core information of Jenkins
 type JenkinsCore struct {
 	JenkinsCrumb
+	// Timeout is the timeout of the HTTP requests, 15 seconds by default.
+	// A value less than one millisecond is taken as seconds, it keeps the old settings like Timeout: 30 working.
 	Timeout            time.Duration
 	URL                string
 	InsecureSkipVerify bool
@@ -35,13 +37,43 @@ type JenkinsCore struct {
 	Token              string
 	Proxy              string
 	ProxyAuth          string
-
+	// NoProxy is a comma-separated list of the hosts which skip the proxy, same as the NO_PROXY environment variable.
+	// localhost and the loopback addresses skip the proxy as well once it is set
+	NoProxy string
+	// ProxyFromEnvironment takes the proxy from HTTP_PROXY, HTTPS_PROXY and NO_PROXY if there is no explicit setting
+	ProxyFromEnvironment bool
+	// CACertFile is a PEM encoded CA bundle which is trusted besides the system ones
+	CACertFile string
+	// Authenticator takes the place of UserName and Token if it is not nil
+	Authenticator Authenticator
+
+	// TransportConfig holds the connection pool settings of the shared HTTP client
+	TransportConfig TransportConfig
+	// RetryPolicy controls how the failed requests are retried, no retry by default
+	RetryPolicy RetryPolicy
+	// Throttle limits the rate and the concurrency of the requests, it could be shared by many clients
+	Throttle *Throttle
+
+	// UserAgent is the User-Agent header of the requests, "jcli; v1.0.0" by default
+	UserAgent string
+	// Logger takes the place of the package Logger if it is not nil
+	Logger *slog.Logger
+
+	// Tracer has the hooks of the HTTP requests
+	Tracer *Tracer
+	// Middlewares wrap all the HTTP requests, see also Use
+	Middlewares []Middleware
+
+	// Debug dumps the HTTP requests and responses to Output, the credentials are redacted
 	Debug        bool
 	Output       io.Writer
 	RoundTripper http.RoundTripper
+
+	clientCache *clientCache
 }
 
-// JenkinsCrumb crumb for Jenkins
+// JenkinsCrumb crumb for Jenkins.
+// The crumb fields of JenkinsCore are not used, the crumb is cached inside and shared by all the copies of a JenkinsCore.
 type JenkinsCrumb struct {
 	CrumbRequestField string
 	Crumb             string
@@ -49,70 +81,82 @@ type JenkinsCrumb struct {
 
 // SetProxy set the proxy for a http
 func SetProxy(proxy, proxyAuth string, tr *http.Transport) (err error) {
-	if proxy == "" {
-		return
-	}
+	return setProxy(proxy, proxyAuth, "", false, tr)
+}
 
-	var proxyURL *url.URL
-	if proxyURL, err = url.Parse(proxy); err != nil {
+// GetClient get the default http Jenkins client.
+// The client and its connection pool are built once and reused by the following requests,
+// a new one is built for each group of the transport related settings, and the copies of this JenkinsCore
+// with other timeouts share the connection pool.
+// The requests of the client fail if the proxy or TLS settings are invalid, see also Validate.
+func (j *JenkinsCore) GetClient() (client *http.Client) {
+	if j.RoundTripper != nil {
+		client = &http.Client{
+			Transport: j.RoundTripper,
+			Timeout:   j.timeout(),
+			Jar:       j.getClientCache().jar,
+		}
 		return
 	}
+	client, _ = j.getPooledClient()
+	return
+}
 
-	tr.Proxy = http.ProxyURL(proxyURL)
-
-	if proxyAuth != "" {
-		basicAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte(proxyAuth))
-		tr.ProxyConnectHeader = http.Header{}
-		tr.ProxyConnectHeader.Add("Proxy-Authorization", basicAuth)
-	}
+// Validate checks the proxy and TLS settings by building the HTTP transport
+func (j *JenkinsCore) Validate() (err error) {
+	_, err = j.getPooledClient()
 	return
 }
 
-// GetClient get the default http Jenkins client
-func (j *JenkinsCore) GetClient() (client *http.Client) {
-	var roundTripper http.RoundTripper
-	if j.RoundTripper != nil {
-		roundTripper = j.RoundTripper
-	} else {
-		tr := &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: j.InsecureSkipVerify},
-		}
-		if err := SetProxy(j.Proxy, j.ProxyAuth, tr); err != nil {
-			log.Fatal(err)
-		}
-		roundTripper = tr
+// timeout makes sure have a default timeout
+func (j *JenkinsCore) timeout() time.Duration {
+	switch {
+	case j.Timeout <= 0:
+		return defaultTimeout
+	case j.Timeout < time.Millisecond:
+		return j.Timeout * time.Second
 	}
+	return j.Timeout
+}
 
-	// make sure have a default timeout here
-	if j.Timeout <= 0 {
-		j.Timeout = 15
+// logger returns the Logger of this JenkinsCore, or the package one
+func (j *JenkinsCore) logger() *slog.Logger {
+	if j.Logger != nil {
+		return j.Logger
 	}
+	return Logger
+}
 
-	client = &http.Client{
-		Transport: roundTripper,
-		Timeout:   j.Timeout * time.Second,
-	}
-	return
+// Clone returns a copy of the JenkinsCore, the HTTP client, the cookies and the crumb are shared with the copy
+func (j *JenkinsCore) Clone() JenkinsCore {
+	j.getClientCache()
+	return *j
 }
 
 // ProxyHandle takes care of the proxy setting
 func (j *JenkinsCore) ProxyHandle(request *http.Request) {
 	if j.ProxyAuth != "" {
 		basicAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte(j.ProxyAuth))
-		Logger.Debug("setting proxy for HTTP request", slog.String("header", basicAuth))
+		j.logger().Debug("setting proxy for HTTP request", slog.String("header", "Basic "+redacted))
 		request.Header.Add("Proxy-Authorization", basicAuth)
 	}
 }
 
 // AuthHandle takes care of the auth
 func (j *JenkinsCore) AuthHandle(request *http.Request) (err error) {
-	if j.UserName != "" && j.Token != "" {
+	if j.Authenticator != nil {
+		if err = j.Authenticator.Authenticate(j, request); err != nil {
+			return
+		}
+	} else if j.UserName != "" && j.Token != "" {
 		request.SetBasicAuth(j.UserName, j.Token)
 	}
 
-	// not add the User-Agent for tests
-	if j.RoundTripper == nil {
-		request.Header.Set("User-Agent", "jcli; v1.0.0")
+	if j.UserAgent != "" {
+		request.Header.Set("User-Agent", j.UserAgent)
+	} else if j.RoundTripper == nil {
+		// not add the default User-Agent for tests
+		request.Header.Set("User-Agent", DefaultUserAgent)
 	}
 
 	j.ProxyHandle(request)
@@ -124,35 +168,47 @@ func (j *JenkinsCore) AuthHandle(request *http.Request) (err error) {
 	return
 }
 
-// CrumbHandle handle crum with http request
+// CrumbHandle handle crum with http request.
+// The crumb is cached and reused until the session of Jenkins changed.
 func (j *JenkinsCore) CrumbHandle(request *http.Request) error {
-	if c, err := j.GetCrumb(); err == nil && c != nil {
+	cache := j.getClientCache()
+	crumb, ok := cache.getCrumb(j.URL)
+	if !ok {
+		c, err := j.GetCrumbContext(request.Context())
+		if err != nil {
+			return err
+		}
 		// cannot get the crumb could be a normal situation
-		j.CrumbRequestField = c.CrumbRequestField
-		j.Crumb = c.Crumb
-		request.Header.Set(j.CrumbRequestField, j.Crumb)
-	} else {
-		return err
+		crumb = c
+		cache.setCrumb(j.URL, crumb)
 	}
 
+	if crumb != nil {
+		request.Header.Set(crumb.CrumbRequestField, crumb.Crumb)
+	}
 	return nil
 }
 
 // GetCrumb get the crumb from Jenkins
 func (j *JenkinsCore) GetCrumb() (crumbIssuer *JenkinsCrumb, err error) {
+	return j.GetCrumbContext(context.Background())
+}
+
+// GetCrumbContext get the crumb from Jenkins with a context
+func (j *JenkinsCore) GetCrumbContext(ctx context.Context) (crumbIssuer *JenkinsCrumb, err error) {
 	var (
-		statusCode int
-		data       []byte
+		response *http.Response
+		data     []byte
 	)
 
-	if statusCode, data, err = j.Request(http.MethodGet, "/crumbIssuer/api/json", nil, nil); err == nil {
-		if statusCode == 200 {
+	if response, data, err = j.do(ctx, http.MethodGet, "/crumbIssuer/api/json", nil, nil, requestOptions{}); err == nil {
+		if response.StatusCode == 200 {
 			err = json.Unmarshal(data, &crumbIssuer)
-		} else if statusCode == 404 {
+		} else if response.StatusCode == 404 {
 			// return 404 if Jenkins does no have crumb
 			// err = fmt.Errorf("crumb is disabled")
 		} else {
-			err = fmt.Errorf("unexpected status code: %d", statusCode)
+			err = j.responseErrorHandle(response, data)
 		}
 	}
 	return
@@ -160,17 +216,23 @@ func (j *JenkinsCore) GetCrumb() (crumbIssuer *JenkinsCrumb, err error) {
 
 // RequestWithData requests the api and parse the data into an interface
 func (j *JenkinsCore) RequestWithData(method, api string, headers map[string]string,
+	payload io.Reader, successCode int, obj interface{}) (err error) {
+	return j.RequestWithDataContext(context.Background(), method, api, headers, payload, successCode, obj)
+}
+
+// RequestWithDataContext requests the api with a context and parse the data into an interface
+func (j *JenkinsCore) RequestWithDataContext(ctx context.Context, method, api string, headers map[string]string,
 	payload io.Reader, successCode int, obj interface{}) (err error) {
 	var (
-		statusCode int
-		data       []byte
+		response *http.Response
+		data     []byte
 	)
 
-	if statusCode, data, err = j.Request(method, api, headers, payload); err == nil {
-		if statusCode == successCode {
+	if response, data, err = j.do(ctx, method, api, headers, payload, requestOptions{}); err == nil {
+		if response.StatusCode == successCode {
 			err = json.Unmarshal(data, obj)
 		} else {
-			err = j.ErrorHandle(statusCode, data)
+			err = j.responseErrorHandle(response, data)
 		}
 	}
 	return
@@ -178,26 +240,37 @@ func (j *JenkinsCore) RequestWithData(method, api string, headers map[string]str
 
 // RequestWithoutData requests the api without handling data
 func (j *JenkinsCore) RequestWithoutData(method, api string, headers map[string]string,
+	payload io.Reader, successCode int) (statusCode int, err error) {
+	return j.RequestWithoutDataContext(context.Background(), method, api, headers, payload, successCode)
+}
+
+// RequestWithoutDataContext requests the api with a context without handling data
+func (j *JenkinsCore) RequestWithoutDataContext(ctx context.Context, method, api string, headers map[string]string,
 	payload io.Reader, successCode int) (statusCode int, err error) {
 	var (
-		data []byte
+		response *http.Response
+		data     []byte
 	)
 
-	if statusCode, data, err = j.Request(method, api, headers, payload); err == nil &&
-		statusCode != successCode {
-		err = j.ErrorHandle(statusCode, data)
+	if response, data, err = j.do(ctx, method, api, headers, payload, requestOptions{}); err == nil {
+		if statusCode = response.StatusCode; statusCode != successCode {
+			err = j.responseErrorHandle(response, data)
+		}
 	}
 	return
 }
 
 // RequestBuilder is a helper for the HTTP request
 type RequestBuilder struct {
+	ctx         context.Context
 	client      *JenkinsCore
 	acceptCodes []int
 	method      string
 	api         string
 	headers     map[string]string
 	payload     io.Reader
+	query       url.Values
+	options     requestOptions
 
 	responseCode int
 	data         []byte
@@ -205,7 +278,13 @@ type RequestBuilder struct {
 
 // NewRequest creates a HTTP request builder instance
 func NewRequest(api string, j *JenkinsCore) *RequestBuilder {
+	return NewRequestWithContext(context.Background(), api, j)
+}
+
+// NewRequestWithContext creates a HTTP request builder instance with a context
+func NewRequestWithContext(ctx context.Context, api string, j *JenkinsCore) *RequestBuilder {
 	return &RequestBuilder{
+		ctx:         ctx,
 		api:         api,
 		method:      http.MethodGet,
 		headers:     map[string]string{},
@@ -214,6 +293,39 @@ func NewRequest(api string, j *JenkinsCore) *RequestBuilder {
 	}
 }
 
+// WithContext sets the context of the request
+func (r *RequestBuilder) WithContext(ctx context.Context) *RequestBuilder {
+	r.ctx = ctx
+	return r
+}
+
+// WithRetry makes the request could be retried according to the RetryPolicy even if it is not idempotent
+func (r *RequestBuilder) WithRetry() *RequestBuilder {
+	r.options.retry = true
+	return r
+}
+
+// Use adds the middlewares to this request, they are inside of the global ones of JenkinsCore
+func (r *RequestBuilder) Use(middlewares ...Middleware) *RequestBuilder {
+	r.options.middlewares = append(r.options.middlewares, middlewares...)
+	return r
+}
+
+// WithQuery sets the query of the request, it is appended to the query of the API if there is one
+func (r *RequestBuilder) WithQuery(query url.Values) *RequestBuilder {
+	r.query = query
+	return r
+}
+
+// AddQuery adds a query parameter
+func (r *RequestBuilder) AddQuery(key, val string) *RequestBuilder {
+	if r.query == nil {
+		r.query = url.Values{}
+	}
+	r.query.Add(key, val)
+	return r
+}
+
 // AcceptStatusCode accept status code
 func (r *RequestBuilder) AcceptStatusCode(code int) *RequestBuilder {
 	r.acceptCodes = append(r.acceptCodes, code)
@@ -292,7 +404,10 @@ func (r *RequestBuilder) GetObject(obj interface{}) error {
 
 // Do runs the HTTP request
 func (r *RequestBuilder) Do() (err error) {
-	if r.responseCode, r.data, err = r.client.Request(r.method, r.api, r.headers, r.payload); err == nil {
+	var response *http.Response
+	api := appendQuery(r.api, r.query)
+	if response, r.data, err = r.client.do(r.ctx, r.method, api, r.headers, r.payload, r.options); err == nil {
+		r.responseCode = response.StatusCode
 		found := false
 		for _, code := range r.acceptCodes {
 			if code == r.responseCode {
@@ -301,41 +416,39 @@ func (r *RequestBuilder) Do() (err error) {
 			}
 		}
 		if !found {
-			err = r.client.ErrorHandle(r.responseCode, r.data)
+			err = r.client.responseErrorHandle(response, r.data)
 		}
 	}
 	return
 }
 
-// ErrorHandle handles the error cases
+// ErrorHandle handles the error cases, the error is an APIError
 func (j *JenkinsCore) ErrorHandle(statusCode int, data []byte) (err error) {
-	if statusCode >= 400 && statusCode < 500 {
-		err = j.PermissionError(statusCode)
-	} else {
-		err = fmt.Errorf("unexpected status code: %d", statusCode)
-	}
-
-	Logger.Debug("get response", slog.String("data", string(data)))
-	return
+	j.logger().Debug("get response", slog.String("data", string(data)))
+	return &APIError{StatusCode: statusCode, Body: trimErrorBody(data)}
 }
 
 // PermissionError handles the no permission
 func (j *JenkinsCore) PermissionError(statusCode int) (err error) {
-	switch statusCode {
-	case 400:
-		err = fmt.Errorf("bad request, code %d", statusCode)
-	case 404:
-		err = fmt.Errorf("not found resources")
-	default:
-		err = fmt.Errorf("the current user has not permission, code %d", statusCode)
-	}
-	return
+	return &APIError{StatusCode: statusCode}
+}
+
+// responseErrorHandle handles the error cases with the details of the response
+func (j *JenkinsCore) responseErrorHandle(response *http.Response, data []byte) error {
+	j.logger().Debug("get response", slog.String("data", string(data)))
+	return NewAPIError(response, data)
 }
 
 // RequestWithResponseHeader make a common request
 func (j *JenkinsCore) RequestWithResponseHeader(method, api string, headers map[string]string, payload io.Reader, obj interface{}) (
 	response *http.Response, err error) {
-	response, err = j.RequestWithResponse(method, api, headers, payload)
+	return j.RequestWithResponseHeaderContext(context.Background(), method, api, headers, payload, obj)
+}
+
+// RequestWithResponseHeaderContext make a common request with a context
+func (j *JenkinsCore) RequestWithResponseHeaderContext(ctx context.Context, method, api string, headers map[string]string, payload io.Reader, obj interface{}) (
+	response *http.Response, err error) {
+	response, err = j.RequestWithResponseContext(ctx, method, api, headers, payload)
 
 	if err == nil && obj != nil && response.StatusCode == 200 {
 		var data []byte
@@ -350,59 +463,144 @@ func (j *JenkinsCore) RequestWithResponseHeader(method, api string, headers map[
 // RequestWithResponse make a common request
 func (j *JenkinsCore) RequestWithResponse(method, api string, headers map[string]string, payload io.Reader) (
 	response *http.Response, err error) {
-	var (
-		req *http.Request
-	)
-
-	if req, err = http.NewRequest(method, fmt.Sprintf("%s%s", j.URL, api), payload); err != nil {
-		return
-	}
-	if err = j.AuthHandle(req); err != nil {
-		return
-	}
-
-	for k, v := range headers {
-		req.Header.Add(k, v)
-	}
-
-	client := j.GetClient()
+	return j.RequestWithResponseContext(context.Background(), method, api, headers, payload)
+}
 
-	return client.Do(req)
+// RequestWithResponseContext make a common request with a context
+func (j *JenkinsCore) RequestWithResponseContext(ctx context.Context, method, api string, headers map[string]string, payload io.Reader) (
+	response *http.Response, err error) {
+	return j.send(ctx, method, fmt.Sprintf("%s%s", j.URL, api), headers, payload, requestOptions{})
 }
 
 // Request make a common request
 func (j *JenkinsCore) Request(method, api string, headers map[string]string, payload io.Reader) (
 	statusCode int, data []byte, err error) {
-	var (
-		req        *http.Request
-		response   *http.Response
-		requestURL string
-	)
+	return j.RequestContext(context.Background(), method, api, headers, payload)
+}
+
+// RequestContext make a common request with a context, the request is canceled once the context is done
+func (j *JenkinsCore) RequestContext(ctx context.Context, method, api string, headers map[string]string, payload io.Reader) (
+	statusCode int, data []byte, err error) {
+	var response *http.Response
+	if response, data, err = j.do(ctx, method, api, headers, payload, requestOptions{}); err == nil {
+		statusCode = response.StatusCode
+	}
+	return
+}
 
+// do sends the HTTP request, then returns the response and its body which has been read
+func (j *JenkinsCore) do(ctx context.Context, method, api string, headers map[string]string, payload io.Reader,
+	options requestOptions) (
+	response *http.Response, data []byte, err error) {
+	var requestURL string
 	if requestURL, err = util.URLJoinAsString(j.URL, api); err != nil {
 		err = fmt.Errorf("cannot parse the URL of Jenkins, error is %v", err)
 		return
 	}
 
-	Logger.Debug("send HTTP request", slog.String("URL", requestURL), slog.String("method", method))
-	if req, err = http.NewRequest(method, requestURL, payload); err != nil {
-		return
+	j.logger().Debug("send HTTP request", slog.String("URL", requestURL), slog.String("method", method))
+	if response, err = j.send(ctx, method, requestURL, headers, payload, options); err == nil {
+		defer func() {
+			_ = response.Body.Close()
+		}()
+		data, err = io.ReadAll(response.Body)
 	}
-	if language != "" {
-		req.Header.Set("Accept-Language", language)
+	return
+}
+
+// requestOptions holds the options of a single request
+type requestOptions struct {
+	// retry makes the request could be retried even if it is not idempotent
+	retry bool
+	// middlewares are only for this request
+	middlewares []Middleware
+}
+
+// send sends the HTTP request, it will be sent again according to the RetryPolicy
+func (j *JenkinsCore) send(ctx context.Context, method, requestURL string, headers map[string]string, payload io.Reader,
+	options requestOptions) (response *http.Response, err error) {
+	// keep the payload, it might be sent more than once
+	var body []byte
+	if payload != nil {
+		if body, err = io.ReadAll(payload); err != nil {
+			return
+		}
 	}
-	if err = j.AuthHandle(req); err != nil {
-		return
+
+	retryable := options.retry || isIdempotentMethod(method)
+	for attempt := 1; ; attempt++ {
+		response, err = j.sendWithCrumb(ctx, method, requestURL, headers, body, options)
+		if !retryable || !j.RetryPolicy.shouldRetry(ctx, attempt, response, err) {
+			return
+		}
+
+		delay := j.RetryPolicy.backoff(attempt, response)
+		j.logger().Debug("retry the HTTP request", slog.String("URL", requestURL), slog.Int("attempt", attempt),
+			slog.Duration("delay", delay), slog.Any("error", err))
+		discardResponse(response)
+		if err = sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
 	}
+}
 
-	for k, v := range headers {
-		req.Header.Add(k, v)
+// sendWithCrumb sends the HTTP request, it will be sent once again with a new crumb if Jenkins rejects the crumb
+func (j *JenkinsCore) sendWithCrumb(ctx context.Context, method, requestURL string, headers map[string]string, body []byte,
+	options requestOptions) (response *http.Response, err error) {
+	doer := j.doer(options)
+	for retried := false; ; retried = true {
+		var payload io.Reader
+		if body != nil {
+			payload = bytes.NewReader(body)
+		}
+
+		var req *http.Request
+		if req, err = http.NewRequestWithContext(ctx, method, requestURL, payload); err != nil {
+			return
+		}
+		if language != "" {
+			req.Header.Set("Accept-Language", language)
+		}
+		if err = j.AuthHandle(req); err != nil {
+			return
+		}
+
+		for k, v := range headers {
+			req.Header.Add(k, v)
+		}
+
+		if response, err = doer.Do(req); err != nil || retried ||
+			method != http.MethodPost || !isInvalidCrumb(response) {
+			return
+		}
+
+		j.logger().Debug("the crumb is invalid, try again with a new one", slog.String("URL", requestURL))
+		_ = response.Body.Close()
+		j.getClientCache().invalidateCrumb()
 	}
+}
 
-	client := j.GetClient()
-	if response, err = client.Do(req); err == nil {
-		statusCode = response.StatusCode
-		data, err = ioutil.ReadAll(response.Body)
+// roundTrip sends the request through the Throttle if there is one, and traces it
+func (j *JenkinsCore) roundTrip(req *http.Request) (response *http.Response, err error) {
+	var release func()
+	if j.Throttle != nil {
+		if release, err = j.Throttle.acquire(req.Context()); err != nil {
+			return
+		}
+	}
+
+	info := j.traceRequest(req)
+	start := time.Now()
+	response, err = j.GetClient().Do(req)
+	j.traceResponse(req, info, response, err, time.Since(start))
+
+	if release != nil {
+		if err != nil {
+			release()
+			return
+		}
+		// the request is still in flight until the body is closed
+		response.Body = &releaseOnClose{ReadCloser: response.Body, release: release}
 	}
 	return
 }
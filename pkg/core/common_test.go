@@ -2,11 +2,16 @@ package core
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
@@ -101,6 +106,141 @@ var _ = Describe("common test", func() {
 		})
 	})
 
+	Context("RequestContext", func() {
+		type ctxKey struct{}
+
+		It("propagate the context to the HTTP request", func() {
+			ctx := context.WithValue(context.Background(), ctxKey{}, "fake")
+			roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				Expect(req.Context().Value(ctxKey{})).To(Equal("fake"))
+				return &http.Response{
+					StatusCode: 200,
+					Request:    req,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+				}, nil
+			})
+
+			statusCode, _, err := jenkinsCore.RequestContext(ctx, http.MethodGet, "/fake", nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusCode).To(Equal(200))
+		})
+
+		It("with a canceled context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				return nil, req.Context().Err()
+			}).AnyTimes()
+
+			err := NewRequestWithContext(ctx, "/fake", &jenkinsCore).WithPostMethod().Do()
+			Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+		})
+	})
+
+	Context("crumb cache", func() {
+		var (
+			paths     []string
+			bodies    []string
+			crumbs    []string
+			responder func(req *http.Request) *http.Response
+		)
+
+		BeforeEach(func() {
+			paths, bodies, crumbs = nil, nil, nil
+			crumbCount := 0
+			responder = func(req *http.Request) *http.Response {
+				response := &http.Response{
+					StatusCode: 200,
+					Header:     http.Header{},
+					Request:    req,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+				}
+				if req.URL.Path == "/crumbIssuer/api/json" {
+					crumbCount++
+					response.Body = ioutil.NopCloser(bytes.NewBufferString(
+						fmt.Sprintf(`{"crumbRequestField":"Jenkins-Crumb","crumb":"crumb-%d"}`, crumbCount)))
+				}
+				return response
+			}
+			roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				paths = append(paths, req.URL.Path)
+				crumbs = append(crumbs, req.Header.Get("Jenkins-Crumb"))
+				if req.Body != nil {
+					data, _ := io.ReadAll(req.Body)
+					bodies = append(bodies, string(data))
+				}
+				return responder(req), nil
+			}).AnyTimes()
+		})
+
+		It("reuse the crumb across the POST requests", func() {
+			Expect(NewRequest("/fake", &jenkinsCore).WithPostMethod().Do()).To(Succeed())
+			copied := jenkinsCore
+			Expect(NewRequest("/fake", &copied).WithPostMethod().Do()).To(Succeed())
+
+			Expect(paths).To(Equal([]string{"/crumbIssuer/api/json", "/fake", "/fake"}))
+			Expect(crumbs).To(Equal([]string{"", "crumb-1", "crumb-1"}))
+		})
+
+		It("fetch a new crumb when the session changed", func() {
+			session := 0
+			defaultResponder := responder
+			responder = func(req *http.Request) *http.Response {
+				response := defaultResponder(req)
+				if req.URL.Path == "/login" {
+					session++
+					response.Header.Add("Set-Cookie", fmt.Sprintf("JSESSIONID.abc=session-%d; Path=/", session))
+				}
+				return response
+			}
+
+			Expect(NewRequest("/login", &jenkinsCore).Do()).To(Succeed())
+			Expect(NewRequest("/fake", &jenkinsCore).WithPostMethod().Do()).To(Succeed())
+			Expect(NewRequest("/login", &jenkinsCore).Do()).To(Succeed())
+			Expect(NewRequest("/fake", &jenkinsCore).WithPostMethod().Do()).To(Succeed())
+
+			Expect(paths).To(Equal([]string{"/login", "/crumbIssuer/api/json", "/fake",
+				"/login", "/crumbIssuer/api/json", "/fake"}))
+			Expect(crumbs[2]).To(Equal("crumb-1"))
+			Expect(crumbs[5]).To(Equal("crumb-2"))
+		})
+
+		It("retry once with a new crumb when the crumb is invalid", func() {
+			rejected := false
+			defaultResponder := responder
+			responder = func(req *http.Request) *http.Response {
+				response := defaultResponder(req)
+				if req.URL.Path == "/fake" && !rejected {
+					rejected = true
+					response.StatusCode = http.StatusForbidden
+					response.Body = ioutil.NopCloser(bytes.NewBufferString("No valid crumb was included in the request"))
+				}
+				return response
+			}
+
+			request := NewRequest("/fake", &jenkinsCore).WithPostMethod().WithValues(url.Values{"name": {"fake"}})
+			Expect(request.Do()).To(Succeed())
+
+			Expect(paths).To(Equal([]string{"/crumbIssuer/api/json", "/fake", "/crumbIssuer/api/json", "/fake"}))
+			Expect(crumbs[3]).To(Equal("crumb-2"))
+			Expect(bodies).To(Equal([]string{"name=fake", "name=fake"}))
+		})
+
+		It("do not retry when the 403 is not caused by the crumb", func() {
+			defaultResponder := responder
+			responder = func(req *http.Request) *http.Response {
+				response := defaultResponder(req)
+				if req.URL.Path == "/fake" {
+					response.StatusCode = http.StatusForbidden
+				}
+				return response
+			}
+
+			Expect(NewRequest("/fake", &jenkinsCore).WithPostMethod().Do()).NotTo(Succeed())
+			Expect(paths).To(Equal([]string{"/crumbIssuer/api/json", "/fake"}))
+		})
+	})
+
 	Context("GetCrumb", func() {
 		It("without crumb setting", func() {
 			requestCrumb, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s", jenkinsCore.URL, "/crumbIssuer/api/json"), nil)
@@ -205,7 +345,7 @@ var _ = Describe("common test", func() {
 				RoundTrip(NewRequestMatcher(requestCrumb)).Return(responseCrumb, nil)
 			err := jenkinsCore.CrumbHandle(requestCrumb)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(Equal("unexpected status code: 500"))
+			Expect(err.Error()).To(Equal("unexpected status code: 500 (GET http://localhost/crumbIssuer/api/json)"))
 		})
 
 		It("handle a request contains crumb in it", func() {
@@ -238,9 +378,96 @@ var _ = Describe("common test", func() {
 			jclient := jenkinsCore.GetClient()
 			Expect(jclient).NotTo(BeNil())
 		})
+
+		It("reuse the HTTP client", func() {
+			jenkinsCore.RoundTripper = nil
+			jclient := jenkinsCore.GetClient()
+			Expect(jenkinsCore.GetClient()).To(BeIdenticalTo(jclient))
+
+			// the copy of a JenkinsCore shares the same client
+			copied := jenkinsCore
+			Expect(copied.GetClient()).To(BeIdenticalTo(jclient))
+
+			transport, ok := jclient.Transport.(*http.Transport)
+			Expect(ok).To(BeTrue())
+			Expect(transport.MaxIdleConnsPerHost).To(Equal(defaultMaxIdleConnsPerHost))
+			Expect(transport.ForceAttemptHTTP2).To(BeTrue())
+		})
+
+		It("rebuild the HTTP client when the settings changed", func() {
+			jenkinsCore.RoundTripper = nil
+			jclient := jenkinsCore.GetClient()
+
+			jenkinsCore.InsecureSkipVerify = true
+			jenkinsCore.TransportConfig = TransportConfig{MaxIdleConnsPerHost: 2, DisableHTTP2: true}
+			newClient := jenkinsCore.GetClient()
+			Expect(newClient).NotTo(BeIdenticalTo(jclient))
+
+			transport, ok := newClient.Transport.(*http.Transport)
+			Expect(ok).To(BeTrue())
+			Expect(transport.MaxIdleConnsPerHost).To(Equal(2))
+			Expect(transport.ForceAttemptHTTP2).To(BeFalse())
+			Expect(transport.TLSClientConfig.InsecureSkipVerify).To(BeTrue())
+		})
+
+		It("share the transport between the copies with different timeouts", func() {
+			jenkinsCore.RoundTripper = nil
+			jclient := jenkinsCore.GetClient()
+
+			copied := jenkinsCore
+			copied.Timeout = time.Minute
+			copiedClient := copied.GetClient()
+			Expect(copiedClient).NotTo(BeIdenticalTo(jclient))
+			Expect(copiedClient.Timeout).To(Equal(time.Minute))
+			Expect(copiedClient.Transport).To(BeIdenticalTo(jclient.Transport))
+
+			// switching between the copies does not rebuild the clients
+			Expect(jenkinsCore.GetClient()).To(BeIdenticalTo(jclient))
+			Expect(copied.GetClient()).To(BeIdenticalTo(copiedClient))
+		})
+
+		It("identify a TLSAuthenticator which is not comparable", func() {
+			jenkinsCore.RoundTripper = nil
+			jenkinsCore.Authenticator = valueCertificateAuth{Certificates: []tls.Certificate{{}}}
+			jclient := jenkinsCore.GetClient()
+			Expect(jenkinsCore.GetClient()).To(BeIdenticalTo(jclient))
+
+			jenkinsCore.Authenticator = valueCertificateAuth{Certificates: []tls.Certificate{{}, {}}}
+			newClient := jenkinsCore.GetClient()
+			Expect(newClient).NotTo(BeIdenticalTo(jclient))
+			Expect(newClient.Transport.(*http.Transport).TLSClientConfig.Certificates).To(HaveLen(2))
+		})
+
+		It("get the HTTP client concurrently", func() {
+			jenkinsCore.RoundTripper = nil
+			clients := make(chan *http.Client, 10)
+			for i := 0; i < cap(clients); i++ {
+				go func() {
+					clients <- jenkinsCore.GetClient()
+				}()
+			}
+
+			first := <-clients
+			for i := 1; i < cap(clients); i++ {
+				Expect(<-clients).To(BeIdenticalTo(first))
+			}
+		})
 	})
 })
 
+// valueCertificateAuth is a TLSAuthenticator which is not comparable
+type valueCertificateAuth struct {
+	Certificates []tls.Certificate
+}
+
+func (a valueCertificateAuth) Authenticate(*JenkinsCore, *http.Request) error {
+	return nil
+}
+
+func (a valueCertificateAuth) ConfigureTLS(config *tls.Config) {
+	config.Certificates = append(config.Certificates, a.Certificates...)
+}
+
 func TestRemoveSliceItem(t *testing.T) {
 	tests := []struct {
 		name   string
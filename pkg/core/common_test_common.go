@@ -9,7 +9,8 @@ import (
 	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
 )
 
-// PrepareForGetIssuer only for test
+// PrepareForGetIssuer only for test.
+// The crumb is cached by JenkinsCore, so the crumb request is expected at most once.
 func PrepareForGetIssuer(roundTripper *mhttp.MockRoundTripper, rootURL, user, password string) (
 	request *http.Request, response *http.Response) {
 	request, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s", rootURL, "/crumbIssuer/api/json"), nil)
@@ -19,7 +20,7 @@ func PrepareForGetIssuer(roundTripper *mhttp.MockRoundTripper, rootURL, user, pa
 		Body:       ioutil.NopCloser(bytes.NewBufferString(`{"CrumbRequestField":"CrumbRequestField","Crumb":"Crumb"}`)),
 	}
 	roundTripper.EXPECT().
-		RoundTrip(NewRequestMatcher(request)).Return(response, nil)
+		RoundTrip(NewRequestMatcher(request)).Return(response, nil).MaxTimes(1)
 	if user != "" && password != "" {
 		request.SetBasicAuth(user, password)
 	}
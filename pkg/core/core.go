@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"encoding/json"
 	"log/slog"
 	"net/url"
@@ -24,9 +25,19 @@ type Client struct {
 	JenkinsCore
 }
 
+// NewClient creates a Client which shares the HTTP client with the JenkinsCore
+func NewClient(jenkins *JenkinsCore) *Client {
+	return &Client{JenkinsCore: jenkins.Clone()}
+}
+
 // Restart will send the restart request
 func (q *Client) Restart() (err error) {
-	request := NewRequest("/safeRestart", &q.JenkinsCore)
+	return q.RestartContext(context.Background())
+}
+
+// RestartContext is the same as Restart but accepts a context
+func (q *Client) RestartContext(ctx context.Context) (err error) {
+	request := NewRequestWithContext(ctx, "/safeRestart", &q.JenkinsCore)
 	request.WithPostMethod().AcceptStatusCode(503)
 	err = request.Do()
 	return
@@ -34,7 +45,12 @@ func (q *Client) Restart() (err error) {
 
 // RestartDirectly restart Jenkins directly
 func (q *Client) RestartDirectly() (err error) {
-	request := NewRequest("/restart", &q.JenkinsCore)
+	return q.RestartDirectlyContext(context.Background())
+}
+
+// RestartDirectlyContext is the same as RestartDirectly but accepts a context
+func (q *Client) RestartDirectlyContext(ctx context.Context) (err error) {
+	request := NewRequestWithContext(ctx, "/restart", &q.JenkinsCore)
 	request.WithPostMethod().AcceptStatusCode(503)
 	err = request.Do()
 	return
@@ -42,11 +58,16 @@ func (q *Client) RestartDirectly() (err error) {
 
 // Shutdown puts Jenkins into the quiet mode, wait for existing builds to be completed, and then shut down Jenkins
 func (q *Client) Shutdown(safe bool) (err error) {
+	return q.ShutdownContext(context.Background(), safe)
+}
+
+// ShutdownContext is the same as Shutdown but accepts a context
+func (q *Client) ShutdownContext(ctx context.Context, safe bool) (err error) {
 	var request *RequestBuilder
 	if safe {
-		request = NewRequest("/safeExit", &q.JenkinsCore)
+		request = NewRequestWithContext(ctx, "/safeExit", &q.JenkinsCore)
 	} else {
-		request = NewRequest("/exit", &q.JenkinsCore)
+		request = NewRequestWithContext(ctx, "/exit", &q.JenkinsCore)
 	}
 	request.WithPostMethod()
 	err = request.Do()
@@ -92,11 +113,16 @@ func (r JSONResult) GetStatus() string {
 // ToJSON turns a Jenkinsfile to JSON format
 // Read details from https://github.com/jenkinsci/pipeline-model-definition-plugin/blob/master/EXTENDING.md
 func (q *Client) ToJSON(jenkinsfile string) (result GenericResult, err error) {
+	return q.ToJSONContext(context.Background(), jenkinsfile)
+}
+
+// ToJSONContext is the same as ToJSON but accepts a context
+func (q *Client) ToJSONContext(ctx context.Context, jenkinsfile string) (result GenericResult, err error) {
 	genericResult := &Result{
 		Data: &JSONResult{},
 	}
 
-	request := NewRequest("/pipeline-model-converter/toJson", &q.JenkinsCore)
+	request := NewRequestWithContext(ctx, "/pipeline-model-converter/toJson", &q.JenkinsCore)
 	request.WithPostMethod().AsFormRequest().WithValues(url.Values{"jenkinsfile": {jenkinsfile}})
 	if err = request.Do(); err == nil {
 		if err = request.GetObject(genericResult); err == nil {
@@ -131,11 +157,16 @@ func (r JenkinsfileResult) GetStatus() string {
 // ToJenkinsfile converts a JSON format data to Jenkinsfile
 // Read details from https://github.com/jenkinsci/pipeline-model-definition-plugin/blob/master/EXTENDING.md
 func (q *Client) ToJenkinsfile(data string) (result GenericResult, err error) {
+	return q.ToJenkinsfileContext(context.Background(), data)
+}
+
+// ToJenkinsfileContext is the same as ToJenkinsfile but accepts a context
+func (q *Client) ToJenkinsfileContext(ctx context.Context, data string) (result GenericResult, err error) {
 	genericResult := &Result{
 		Data: &JenkinsfileResult{},
 	}
 
-	request := NewRequest("/pipeline-model-converter/toJenkinsfile", &q.JenkinsCore)
+	request := NewRequestWithContext(ctx, "/pipeline-model-converter/toJenkinsfile", &q.JenkinsCore)
 	request.WithPostMethod().AsFormRequest().WithValues(url.Values{"json": {data}})
 	if err = request.Do(); err == nil {
 		if err = request.GetObject(genericResult); err == nil {
@@ -178,8 +209,13 @@ type AgentLabel struct {
 // GetLabels returns the labels of all the Jenkins agents
 // Read details from https://github.com/jenkinsci/label-linked-jobs-plugin
 func (q *Client) GetLabels() (labelsRes *LabelsResponse, err error) {
+	return q.GetLabelsContext(context.Background())
+}
+
+// GetLabelsContext is the same as GetLabels but accepts a context
+func (q *Client) GetLabelsContext(ctx context.Context) (labelsRes *LabelsResponse, err error) {
 	labelsRes = &LabelsResponse{}
-	request := NewRequest("/labelsdashboard/labelsData", &q.JenkinsCore)
+	request := NewRequestWithContext(ctx, "/labelsdashboard/labelsData", &q.JenkinsCore)
 	if err = request.Do(); err == nil {
 		err = request.GetObject(labelsRes)
 	}
@@ -188,13 +224,18 @@ func (q *Client) GetLabels() (labelsRes *LabelsResponse, err error) {
 
 // PrepareShutdown Put Jenkins in a Quiet mode, in preparation for a restart. In that mode Jenkins don’t start any build
 func (q *Client) PrepareShutdown(cancel bool) (err error) {
+	return q.PrepareShutdownContext(context.Background(), cancel)
+}
+
+// PrepareShutdownContext is the same as PrepareShutdown but accepts a context
+func (q *Client) PrepareShutdownContext(ctx context.Context, cancel bool) (err error) {
 	var api string
 	if cancel {
 		api = "/cancelQuietDown"
 	} else {
 		api = "/quietDown"
 	}
-	request := NewRequest(api, &q.JenkinsCore)
+	request := NewRequestWithContext(ctx, api, &q.JenkinsCore)
 	request.WithPostMethod()
 	err = request.Do()
 	return
@@ -209,7 +250,12 @@ type JenkinsIdentity struct {
 
 // GetIdentity returns the identity of a Jenkins
 func (q *Client) GetIdentity() (identity JenkinsIdentity, err error) {
-	request := NewRequest("/instance", &q.JenkinsCore)
+	return q.GetIdentityContext(context.Background())
+}
+
+// GetIdentityContext is the same as GetIdentity but accepts a context
+func (q *Client) GetIdentityContext(ctx context.Context) (identity JenkinsIdentity, err error) {
+	request := NewRequestWithContext(ctx, "/instance", &q.JenkinsCore)
 	if err = request.Do(); err == nil {
 		err = request.GetObject(&identity)
 	}
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"context"
+	"net/http"
 	"reflect"
 	"testing"
 
@@ -164,6 +166,36 @@ var _ = Describe("core test", func() {
 		})
 	})
 
+	Context("cancelled context", func() {
+		var ctx context.Context
+
+		BeforeEach(func() {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithCancel(context.Background())
+			cancel()
+			roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				return nil, req.Context().Err()
+			}).AnyTimes()
+		})
+
+		It("should fail all the requests", func() {
+			Expect(coreClient.RestartContext(ctx)).To(MatchError(context.Canceled))
+			Expect(coreClient.RestartDirectlyContext(ctx)).To(MatchError(context.Canceled))
+			Expect(coreClient.ShutdownContext(ctx, true)).To(MatchError(context.Canceled))
+			Expect(coreClient.ShutdownContext(ctx, false)).To(MatchError(context.Canceled))
+			Expect(coreClient.PrepareShutdownContext(ctx, false)).To(MatchError(context.Canceled))
+
+			_, err := coreClient.ToJSONContext(ctx, "pipeline {}")
+			Expect(err).To(MatchError(context.Canceled))
+			_, err = coreClient.ToJenkinsfileContext(ctx, "{}")
+			Expect(err).To(MatchError(context.Canceled))
+			_, err = coreClient.GetLabelsContext(ctx)
+			Expect(err).To(MatchError(context.Canceled))
+			_, err = coreClient.GetIdentityContext(ctx)
+			Expect(err).To(MatchError(context.Canceled))
+		})
+	})
+
 	Context("GetLabels", func() {
 		var (
 			labels *LabelsResponse
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// crumbCache keeps the crumb of a Jenkins session.
+// Jenkins binds the crumb with the session, so the crumb is valid as long as the session cookie does not change.
+type crumbCache struct {
+	lock    sync.RWMutex
+	fetched bool
+	url     string
+	session string
+	crumb   *JenkinsCrumb
+}
+
+// getCrumb returns the cached crumb, the second value is false if there is no valid crumb in the cache.
+// A nil crumb is valid when the crumb is disabled in Jenkins.
+func (c *clientCache) getCrumb(jenkinsURL string) (crumb *JenkinsCrumb, ok bool) {
+	session := c.session(jenkinsURL)
+
+	c.crumb.lock.RLock()
+	defer c.crumb.lock.RUnlock()
+	if c.crumb.fetched && c.crumb.url == jenkinsURL && c.crumb.session == session {
+		crumb, ok = c.crumb.crumb, true
+	}
+	return
+}
+
+// setCrumb stores the crumb with the current session
+func (c *clientCache) setCrumb(jenkinsURL string, crumb *JenkinsCrumb) {
+	session := c.session(jenkinsURL)
+
+	c.crumb.lock.Lock()
+	defer c.crumb.lock.Unlock()
+	c.crumb.fetched = true
+	c.crumb.url = jenkinsURL
+	c.crumb.session = session
+	c.crumb.crumb = crumb
+}
+
+// invalidateCrumb removes the cached crumb
+func (c *clientCache) invalidateCrumb() {
+	c.crumb.lock.Lock()
+	defer c.crumb.lock.Unlock()
+	c.crumb.fetched = false
+	c.crumb.crumb = nil
+}
+
+// session returns the session cookies of Jenkins, the cookie name looks like JSESSIONID.a1b2c3d4
+func (c *clientCache) session(jenkinsURL string) string {
+	u, err := url.Parse(jenkinsURL)
+	if err != nil || c.jar == nil {
+		return ""
+	}
+
+	var sessions []string
+	for _, cookie := range c.jar.Cookies(u) {
+		if strings.HasPrefix(cookie.Name, "JSESSIONID") {
+			sessions = append(sessions, cookie.Name+"="+cookie.Value)
+		}
+	}
+	return strings.Join(sessions, ";")
+}
+
+// isInvalidCrumb checks if Jenkins rejects the request due to an invalid crumb.
+// The body of the response is still readable after this checking.
+func isInvalidCrumb(response *http.Response) bool {
+	if response.StatusCode != http.StatusForbidden || response.Body == nil {
+		return false
+	}
+
+	data, err := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(data))
+	return err == nil && bytes.Contains(data, []byte("No valid crumb"))
+}
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxErrorBodySize is the max length of the response body kept in an APIError
+const maxErrorBodySize = 1024
+
+var (
+	// ErrNotFound matches the API errors with status code 404
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized matches the API errors with status code 401
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden matches the API errors with status code 403
+	ErrForbidden = errors.New("forbidden")
+	// ErrConflict matches the API errors with status code 409
+	ErrConflict = errors.New("conflict")
+	// ErrServerError matches the API errors with status code 5xx
+	ErrServerError = errors.New("server error")
+)
+
+// APIError represents an unexpected response from Jenkins
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	// JenkinsVersion comes from the header X-Jenkins of the response
+	JenkinsVersion string
+	// Body is the trimmed response body
+	Body string
+}
+
+// NewAPIError creates an APIError from the response and its body, the response could be nil
+func NewAPIError(response *http.Response, data []byte) *APIError {
+	apiErr := &APIError{Body: trimErrorBody(data)}
+	if response != nil {
+		apiErr.StatusCode = response.StatusCode
+		apiErr.JenkinsVersion = response.Header.Get("X-Jenkins")
+		if response.Request != nil {
+			apiErr.Method = response.Request.Method
+			if response.Request.URL != nil {
+				apiErr.URL = response.Request.URL.Redacted()
+			}
+		}
+	}
+	return apiErr
+}
+
+// Error returns the message of this error, the method and URL of the request are appended if there are
+func (e *APIError) Error() string {
+	var message string
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		message = fmt.Sprintf("bad request, code %d", e.StatusCode)
+	case e.StatusCode == http.StatusNotFound:
+		message = "not found resources"
+	case e.StatusCode > 400 && e.StatusCode < 500:
+		message = fmt.Sprintf("the current user has not permission, code %d", e.StatusCode)
+	default:
+		message = fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+	}
+	if e.URL != "" {
+		request := e.URL
+		if e.Method != "" {
+			request = e.Method + " " + request
+		}
+		message = fmt.Sprintf("%s (%s)", message, request)
+	}
+	return message
+}
+
+// Is makes the APIError could be checked by errors.Is with the sentinel errors, such as ErrNotFound
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrServerError:
+		return e.StatusCode >= 500 && e.StatusCode < 600
+	}
+	return false
+}
+
+// IsNotFound returns true if the error is caused by status code 404
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsUnauthorized returns true if the error is caused by status code 401
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsForbidden returns true if the error is caused by status code 403
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsConflict returns true if the error is caused by status code 409
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsServerError returns true if the error is caused by status code 5xx
+func IsServerError(err error) bool {
+	return errors.Is(err, ErrServerError)
+}
+
+// trimErrorBody keeps maxErrorBodySize bytes of the body at most, a multi-byte character is not split
+func trimErrorBody(data []byte) string {
+	body := strings.TrimSpace(string(data))
+	if len(body) > maxErrorBodySize {
+		end := maxErrorBodySize
+		for end > 0 && !utf8.RuneStart(body[end]) {
+			end--
+		}
+		body = body[:end] + "..."
+	}
+	return body
+}
@@ -0,0 +1,105 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("APIError test", func() {
+	var (
+		ctrl         *gomock.Controller
+		jenkinsCore  JenkinsCore
+		roundTripper *mhttp.MockRoundTripper
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		jenkinsCore = JenkinsCore{}
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		jenkinsCore.RoundTripper = roundTripper
+		jenkinsCore.URL = "http://localhost"
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("carry the details of the response", func() {
+		request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/job/fake/api/json", jenkinsCore.URL), nil)
+		response := &http.Response{
+			StatusCode: 404,
+			Header:     http.Header{"X-Jenkins": {"2.414.1"}},
+			Request:    request,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("  not found  ")),
+		}
+		roundTripper.EXPECT().
+			RoundTrip(NewRequestMatcher(request)).Return(response, nil)
+
+		err := jenkinsCore.RequestWithData(http.MethodGet, "/job/fake/api/json", nil, nil, 200, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("not found resources (GET http://localhost/job/fake/api/json)"))
+		Expect(IsNotFound(err)).To(BeTrue())
+		Expect(IsServerError(err)).To(BeFalse())
+
+		var apiErr *APIError
+		Expect(errors.As(err, &apiErr)).To(BeTrue())
+		Expect(apiErr.StatusCode).To(Equal(404))
+		Expect(apiErr.Method).To(Equal(http.MethodGet))
+		Expect(apiErr.URL).To(Equal("http://localhost/job/fake/api/json"))
+		Expect(apiErr.JenkinsVersion).To(Equal("2.414.1"))
+		Expect(apiErr.Body).To(Equal("not found"))
+	})
+
+	It("match the sentinel errors", func() {
+		cases := map[int]error{
+			http.StatusNotFound:            ErrNotFound,
+			http.StatusUnauthorized:        ErrUnauthorized,
+			http.StatusForbidden:           ErrForbidden,
+			http.StatusConflict:            ErrConflict,
+			http.StatusInternalServerError: ErrServerError,
+			http.StatusServiceUnavailable:  ErrServerError,
+		}
+		for code, target := range cases {
+			err := fmt.Errorf("wrapped: %w", jenkinsCore.ErrorHandle(code, nil))
+			Expect(errors.Is(err, target)).To(BeTrue(), "status code %d", code)
+		}
+
+		err := jenkinsCore.ErrorHandle(http.StatusBadRequest, nil)
+		for _, target := range []error{ErrNotFound, ErrUnauthorized, ErrForbidden, ErrConflict, ErrServerError} {
+			Expect(errors.Is(err, target)).To(BeFalse())
+		}
+		Expect(IsUnauthorized(jenkinsCore.PermissionError(401))).To(BeTrue())
+		Expect(IsForbidden(jenkinsCore.PermissionError(403))).To(BeTrue())
+		Expect(IsConflict(jenkinsCore.PermissionError(409))).To(BeTrue())
+	})
+
+	It("trim the long body", func() {
+		apiErr := NewAPIError(nil, []byte(strings.Repeat("a", maxErrorBodySize+10)))
+		Expect(apiErr.Body).To(HaveLen(maxErrorBodySize + 3))
+		Expect(apiErr.Body).To(HaveSuffix("..."))
+	})
+
+	It("trim the long body on a character boundary", func() {
+		// the 3 bytes character crosses the limit
+		apiErr := NewAPIError(nil, []byte(strings.Repeat("a", maxErrorBodySize-1)+"流水线"))
+		Expect(utf8.ValidString(apiErr.Body)).To(BeTrue())
+		Expect(apiErr.Body).To(Equal(strings.Repeat("a", maxErrorBodySize-1) + "..."))
+	})
+
+	It("include the request in the message", func() {
+		request, _ := http.NewRequest(http.MethodPost, "http://localhost/job/fake/build", nil)
+		err := NewAPIError(&http.Response{StatusCode: http.StatusForbidden, Request: request}, nil)
+		Expect(err.Error()).To(Equal("the current user has not permission, code 403 (POST http://localhost/job/fake/build)"))
+		Expect(NewAPIError(nil, nil).Error()).To(Equal("unexpected status code: 0"))
+	})
+})
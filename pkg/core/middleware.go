@@ -0,0 +1,56 @@
+package core
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Doer sends a HTTP request to Jenkins
+type Doer interface {
+	Do(request *http.Request) (*http.Response, error)
+}
+
+// DoerFunc is an adapter to allow the use of ordinary functions as Doer
+type DoerFunc func(request *http.Request) (*http.Response, error)
+
+// Do calls f(request)
+func (f DoerFunc) Do(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+// Middleware wraps a Doer, it could change the request, the response or not call the next one at all.
+// The request has the auth and crumb headers already, a middleware is called on each attempt of the request.
+type Middleware func(next Doer) Doer
+
+// chain wraps the Doer with the middlewares, the first middleware is the outermost one
+func chain(doer Doer, middlewares ...[]Middleware) Doer {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		for j := len(middlewares[i]) - 1; j >= 0; j-- {
+			doer = middlewares[i][j](doer)
+		}
+	}
+	return doer
+}
+
+// Use adds the middlewares to all the requests of this JenkinsCore
+func (j *JenkinsCore) Use(middlewares ...Middleware) {
+	// never share the backing array with the copies of this JenkinsCore
+	j.Middlewares = append(j.Middlewares[:len(j.Middlewares):len(j.Middlewares)], middlewares...)
+}
+
+// doer returns the Doer of a request, the global middlewares are outside of the ones of the request
+func (j *JenkinsCore) doer(options requestOptions) Doer {
+	return chain(DoerFunc(j.roundTrip), j.Middlewares, options.middlewares)
+}
+
+// appendQuery appends the query to the API which might have a query already
+func appendQuery(api string, query url.Values) string {
+	if len(query) == 0 {
+		return api
+	}
+	if strings.Contains(api, "?") {
+		return api + "&" + query.Encode()
+	}
+	return api + "?" + query.Encode()
+}
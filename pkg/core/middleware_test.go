@@ -0,0 +1,112 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("middleware test", func() {
+	var (
+		ctrl         *gomock.Controller
+		jenkinsCore  JenkinsCore
+		roundTripper *mhttp.MockRoundTripper
+		calls        []string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		jenkinsCore = JenkinsCore{}
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		jenkinsCore.RoundTripper = roundTripper
+		jenkinsCore.URL = "http://localhost"
+		calls = nil
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	record := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(request *http.Request) (*http.Response, error) {
+				calls = append(calls, name)
+				request.Header.Set("X-"+name, "true")
+				return next.Do(request)
+			})
+		}
+	}
+
+	okResponse := func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Request:    req,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("{}")),
+		}, nil
+	}
+
+	It("call the global middlewares then the ones of the request", func() {
+		jenkinsCore.Use(record("global"))
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			Expect(req.Header.Get("X-global")).To(Equal("true"))
+			Expect(req.Header.Get("X-request")).To(Equal("true"))
+			return okResponse(req)
+		})
+
+		err := NewRequest("/fake", &jenkinsCore).Use(record("request")).Do()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal([]string{"global", "request"}))
+	})
+
+	It("short-circuit the request", func() {
+		jenkinsCore.Use(func(next Doer) Doer {
+			return DoerFunc(func(request *http.Request) (*http.Response, error) {
+				return nil, errors.New("fault injection")
+			})
+		})
+
+		err := NewRequest("/fake", &jenkinsCore).Do()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("fault injection"))
+	})
+
+	It("the copies do not share the added middlewares", func() {
+		jenkinsCore.Use(record("a"), record("b"))
+		clone := jenkinsCore.Clone()
+		clone.Use(record("clone"))
+		jenkinsCore.Use(record("origin"))
+		Expect(clone.Middlewares).To(HaveLen(3))
+		Expect(jenkinsCore.Middlewares).To(HaveLen(3))
+
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(okResponse)
+		Expect(NewRequest("/fake", &clone).Do()).To(Succeed())
+		Expect(calls).To(Equal([]string{"a", "b", "clone"}))
+	})
+
+	It("with query", func() {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			Expect(req.URL.Path).To(Equal("/fake"))
+			Expect(req.URL.Query()).To(Equal(url.Values{
+				"depth": {"1"},
+				"name":  {"a b"},
+				"tree":  {"jobs[name]"},
+			}))
+			return okResponse(req)
+		}).Times(2)
+
+		err := NewRequest("/fake?depth=1", &jenkinsCore).WithQuery(url.Values{"name": {"a b"}}).
+			AddQuery("tree", "jobs[name]").Do()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = NewRequest("/fake", &jenkinsCore).AddQuery("depth", "1").AddQuery("name", "a b").
+			AddQuery("tree", "jobs[name]").Do()
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
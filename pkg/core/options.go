@@ -0,0 +1,220 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Option is the setting of JenkinsCore
+type Option func(*JenkinsCore) error
+
+// New creates a JenkinsCore with the options, it returns an error if the proxy or TLS settings are invalid
+func New(opts ...Option) (j *JenkinsCore, err error) {
+	j = &JenkinsCore{}
+	for _, opt := range opts {
+		if err = opt(j); err != nil {
+			return nil, err
+		}
+	}
+	if err = j.Validate(); err != nil {
+		j = nil
+	}
+	return
+}
+
+// NewJenkinsCore creates a JenkinsCore for the Jenkins URL with the options.
+// The copies of it share the HTTP client, see also Clone.
+func NewJenkinsCore(jenkinsURL string, opts ...Option) (*JenkinsCore, error) {
+	return New(append([]Option{WithURL(jenkinsURL)}, opts...)...)
+}
+
+// WithURL sets the URL of Jenkins, it must be an absolute HTTP or HTTPS URL
+func WithURL(jenkinsURL string) Option {
+	return func(j *JenkinsCore) (err error) {
+		var u *url.URL
+		if u, err = url.Parse(jenkinsURL); err != nil {
+			return fmt.Errorf("invalid Jenkins URL %q, error is %v", jenkinsURL, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid Jenkins URL %q, it must be an absolute HTTP or HTTPS URL", jenkinsURL)
+		}
+		j.URL = strings.TrimSuffix(jenkinsURL, "/")
+		return
+	}
+}
+
+// WithBasicAuth authenticates the requests with the username and the API token
+func WithBasicAuth(userName, token string) Option {
+	return func(j *JenkinsCore) error {
+		if userName == "" || token == "" {
+			return errors.New("both the username and the token are required")
+		}
+		j.UserName = userName
+		j.Token = token
+		return nil
+	}
+}
+
+// WithAuthenticator authenticates the requests with the Authenticator
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(j *JenkinsCore) error {
+		if authenticator == nil {
+			return errors.New("the authenticator is nil")
+		}
+		j.Authenticator = authenticator
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of the HTTP requests
+func WithTimeout(timeout time.Duration) Option {
+	return func(j *JenkinsCore) error {
+		if timeout < time.Millisecond {
+			return fmt.Errorf("the timeout %v is too short, it should be at least one millisecond", timeout)
+		}
+		j.Timeout = timeout
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests
+func WithUserAgent(userAgent string) Option {
+	return func(j *JenkinsCore) error {
+		j.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithLogger sets the logger of this JenkinsCore instead of the package one
+func WithLogger(logger *slog.Logger) Option {
+	return func(j *JenkinsCore) error {
+		j.Logger = logger
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the retry policy, see also DefaultRetryPolicy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(j *JenkinsCore) error {
+		if policy.MaxAttempts < 0 || policy.InitialBackoff < 0 || policy.MaxBackoff < 0 {
+			return errors.New("the retry policy must not be negative")
+		}
+		j.RetryPolicy = policy
+		return nil
+	}
+}
+
+// WithThrottle limits the rate and the concurrency of the requests
+func WithThrottle(throttle *Throttle) Option {
+	return func(j *JenkinsCore) error {
+		j.Throttle = throttle
+		return nil
+	}
+}
+
+// WithTransportConfig sets the connection pool of the HTTP client
+func WithTransportConfig(config TransportConfig) Option {
+	return func(j *JenkinsCore) error {
+		j.TransportConfig = config
+		return nil
+	}
+}
+
+// WithTracer sets the hooks of the HTTP requests
+func WithTracer(tracer *Tracer) Option {
+	return func(j *JenkinsCore) error {
+		j.Tracer = tracer
+		return nil
+	}
+}
+
+// WithMiddlewares adds the middlewares to all the requests
+func WithMiddlewares(middlewares ...Middleware) Option {
+	return func(j *JenkinsCore) error {
+		j.Use(middlewares...)
+		return nil
+	}
+}
+
+// WithDebug dumps the HTTP requests and responses to the output
+func WithDebug(output io.Writer) Option {
+	return func(j *JenkinsCore) error {
+		if output == nil {
+			return errors.New("the debug output is nil")
+		}
+		j.Debug = true
+		j.Output = output
+		return nil
+	}
+}
+
+// WithRoundTripper sends the requests via the RoundTripper instead of the shared HTTP client, it is useful for tests
+func WithRoundTripper(roundTripper http.RoundTripper) Option {
+	return func(j *JenkinsCore) error {
+		j.RoundTripper = roundTripper
+		return nil
+	}
+}
+
+// WithProxy sets the proxy address and the credentials in the form of username:password.
+// The address could be a HTTP, HTTPS or SOCKS5 proxy, e.g. socks5://localhost:1080
+func WithProxy(proxy, proxyAuth string) Option {
+	return func(j *JenkinsCore) (err error) {
+		if _, err = parseProxyURL(proxy); err == nil {
+			j.Proxy = proxy
+			j.ProxyAuth = proxyAuth
+		}
+		return
+	}
+}
+
+// WithNoProxy sets the comma-separated hosts which skip the proxy
+func WithNoProxy(noProxy string) Option {
+	return func(j *JenkinsCore) error {
+		j.NoProxy = noProxy
+		return nil
+	}
+}
+
+// WithProxyFromEnvironment takes the proxy from the environment variables HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+func WithProxyFromEnvironment() Option {
+	return func(j *JenkinsCore) error {
+		j.ProxyFromEnvironment = true
+		return nil
+	}
+}
+
+// WithClientCertificate authenticates with the PEM encoded client certificate and key files
+func WithClientCertificate(certFile, keyFile string) Option {
+	return func(j *JenkinsCore) (err error) {
+		var auth *ClientCertificateAuth
+		if auth, err = NewClientCertificateAuth(certFile, keyFile); err == nil {
+			j.Authenticator = auth
+		}
+		return
+	}
+}
+
+// WithCACertFile trusts the PEM encoded CA bundle besides the system ones
+func WithCACertFile(caCertFile string) Option {
+	return func(j *JenkinsCore) (err error) {
+		if _, err = loadCACertFile(caCertFile); err == nil {
+			j.CACertFile = caCertFile
+		}
+		return
+	}
+}
+
+// WithInsecureSkipVerify skips the verification of the server certificates
+func WithInsecureSkipVerify() Option {
+	return func(j *JenkinsCore) error {
+		j.InsecureSkipVerify = true
+		return nil
+	}
+}
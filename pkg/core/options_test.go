@@ -0,0 +1,94 @@
+package core
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("options test", func() {
+	Context("NewJenkinsCore", func() {
+		It("with valid options", func() {
+			jenkinsCore, err := NewJenkinsCore("https://ci.example.com/",
+				WithBasicAuth("admin", "token"),
+				WithTimeout(5*time.Second),
+				WithUserAgent("fake"),
+				WithRetryPolicy(DefaultRetryPolicy()))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jenkinsCore.URL).To(Equal("https://ci.example.com"))
+			Expect(jenkinsCore.UserName).To(Equal("admin"))
+			Expect(jenkinsCore.UserAgent).To(Equal("fake"))
+			Expect(jenkinsCore.GetClient().Timeout).To(Equal(5 * time.Second))
+		})
+
+		It("with invalid options", func() {
+			_, err := NewJenkinsCore("ci.example.com")
+			Expect(err).To(HaveOccurred())
+
+			_, err = NewJenkinsCore("http://localhost", WithTimeout(5))
+			Expect(err).To(HaveOccurred())
+
+			_, err = NewJenkinsCore("http://localhost", WithBasicAuth("admin", ""))
+			Expect(err).To(HaveOccurred())
+
+			_, err = NewJenkinsCore("http://localhost", WithRetryPolicy(RetryPolicy{MaxAttempts: -1}))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("timeout", func() {
+		It("default timeout", func() {
+			Expect((&JenkinsCore{}).GetClient().Timeout).To(Equal(15 * time.Second))
+		})
+
+		It("take the legacy value as seconds", func() {
+			Expect((&JenkinsCore{Timeout: 30}).GetClient().Timeout).To(Equal(30 * time.Second))
+		})
+
+		It("take a duration as it is", func() {
+			Expect((&JenkinsCore{Timeout: 2 * time.Minute}).GetClient().Timeout).To(Equal(2 * time.Minute))
+		})
+	})
+
+	It("send the requests with the user agent and the logger", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		defer ctrl.Finish()
+		roundTripper := mhttp.NewMockRoundTripper(ctrl)
+
+		buf := &bytes.Buffer{}
+		logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		jenkinsCore, err := NewJenkinsCore("http://localhost", WithRoundTripper(roundTripper),
+			WithUserAgent("fake-agent"), WithLogger(logger))
+		Expect(err).NotTo(HaveOccurred())
+
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			Expect(req.Header.Get("User-Agent")).To(Equal("fake-agent"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Request:    req,
+				Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+			}, nil
+		})
+		Expect(NewRequest("/fake", jenkinsCore).Do()).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("send HTTP request"))
+	})
+
+	It("the clients share the HTTP client of the JenkinsCore", func() {
+		jenkinsCore, err := NewJenkinsCore("http://localhost")
+		Expect(err).NotTo(HaveOccurred())
+
+		client := NewClient(jenkinsCore)
+		Expect(client.GetClient()).To(BeIdenticalTo(jenkinsCore.GetClient()))
+
+		legacy := &JenkinsCore{URL: "http://localhost"}
+		clone := legacy.Clone()
+		Expect(clone.GetClient()).To(BeIdenticalTo(legacy.GetClient()))
+	})
+})
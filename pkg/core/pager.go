@@ -0,0 +1,90 @@
+package core
+
+import "context"
+
+// DefaultPageSize is the page size of a Pager if there is no one
+const DefaultPageSize = 50
+
+// PageFunc fetches a page of the items, start is the index of the first item
+type PageFunc[T any] func(ctx context.Context, start, limit int) ([]T, error)
+
+// Pager walks through all the pages of a list API lazily.
+// A page which has less items than the page size is taken as the last one.
+// It is not safe for concurrent use.
+type Pager[T any] struct {
+	fetch    PageFunc[T]
+	pageSize int
+	start    int
+	done     bool
+	err      error
+}
+
+// NewPager creates a Pager, the DefaultPageSize is used if the pageSize is not positive
+func NewPager[T any](pageSize int, fetch PageFunc[T]) *Pager[T] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	return &Pager[T]{fetch: fetch, pageSize: pageSize}
+}
+
+// NextPage fetches the next page, the items are empty if there are no more pages
+func (p *Pager[T]) NextPage(ctx context.Context) (items []T, err error) {
+	if p.done {
+		return nil, p.err
+	}
+	if err = ctx.Err(); err == nil {
+		items, err = p.fetch(ctx, p.start, p.pageSize)
+	}
+	if err != nil {
+		p.done, p.err = true, err
+		return nil, err
+	}
+
+	p.start += len(items)
+	p.done = len(items) < p.pageSize
+	return
+}
+
+// HasNext returns false if all the pages have been fetched or there was an error
+func (p *Pager[T]) HasNext() bool {
+	return !p.done
+}
+
+// Err returns the error which stopped the Pager
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// All returns an iterator of all the items in the following pages, the pages are fetched when needed.
+// It is compatible with iter.Seq2[T, error], an error is yielded with the zero value at last if there is one.
+// The rest items of the current page are dropped if the iteration stops early.
+func (p *Pager[T]) All(ctx context.Context) func(yield func(T, error) bool) {
+	return func(yield func(T, error) bool) {
+		for p.HasNext() {
+			items, err := p.NextPage(ctx)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Collect fetches all the items in the following pages
+func (p *Pager[T]) Collect(ctx context.Context) (items []T, err error) {
+	p.All(ctx)(func(item T, e error) bool {
+		if e != nil {
+			err = e
+			return false
+		}
+		items = append(items, item)
+		return true
+	})
+	return
+}
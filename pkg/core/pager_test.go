@@ -0,0 +1,88 @@
+package core
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pager test", func() {
+	var (
+		total  int
+		starts []int
+		fetch  PageFunc[int]
+	)
+
+	BeforeEach(func() {
+		total = 7
+		starts = nil
+		fetch = func(ctx context.Context, start, limit int) (items []int, err error) {
+			starts = append(starts, start)
+			for i := start; i < total && i < start+limit; i++ {
+				items = append(items, i)
+			}
+			return
+		}
+	})
+
+	It("walk through all the pages", func() {
+		items, err := NewPager(3, fetch).Collect(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items).To(Equal([]int{0, 1, 2, 3, 4, 5, 6}))
+		Expect(starts).To(Equal([]int{0, 3, 6}))
+	})
+
+	It("fetch one more page if the last page is full", func() {
+		total = 6
+		items, err := NewPager(3, fetch).Collect(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items).To(HaveLen(6))
+		Expect(starts).To(Equal([]int{0, 3, 6}))
+	})
+
+	It("fetch the pages lazily", func() {
+		var items []int
+		NewPager(3, fetch).All(context.Background())(func(item int, err error) bool {
+			items = append(items, item)
+			return len(items) < 4
+		})
+		Expect(items).To(Equal([]int{0, 1, 2, 3}))
+		Expect(starts).To(Equal([]int{0, 3}))
+	})
+
+	It("with the default page size", func() {
+		pager := NewPager(0, fetch)
+		items, err := pager.NextPage(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items).To(HaveLen(7))
+		Expect(pager.HasNext()).To(BeFalse())
+	})
+
+	It("stop on error", func() {
+		pager := NewPager(3, func(ctx context.Context, start, limit int) ([]int, error) {
+			if start > 0 {
+				return nil, errors.New("fake")
+			}
+			return fetch(ctx, start, limit)
+		})
+		items, err := pager.Collect(context.Background())
+		Expect(err).To(HaveOccurred())
+		Expect(items).To(Equal([]int{0, 1, 2}))
+		Expect(pager.Err()).To(Equal(err))
+		Expect(pager.HasNext()).To(BeFalse())
+	})
+
+	It("stop on the context cancellation", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		pager := NewPager(3, fetch)
+		_, err := pager.NextPage(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		cancel()
+		_, err = pager.NextPage(ctx)
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(starts).To(Equal([]int{0}))
+	})
+})
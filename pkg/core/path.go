@@ -0,0 +1,151 @@
+package core
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ItemPath is the path of a Jenkins item, such as a job in folders or a branch of a multibranch project.
+// It holds the item names from the root, each name is escaped when building the URL path.
+type ItemPath []string
+
+// NewItemPath creates an ItemPath with the item names from the root
+func NewItemPath(names ...string) ItemPath {
+	return append(ItemPath{}, names...)
+}
+
+// ParseFullName parses the full name of an item which is separated by slashes, e.g. folder/job
+func ParseFullName(fullName string) ItemPath {
+	fullName = strings.Trim(fullName, "/")
+	if fullName == "" {
+		return ItemPath{}
+	}
+	return NewItemPath(strings.Split(fullName, "/")...)
+}
+
+// Job returns the path of a child item
+func (p ItemPath) Job(name string) ItemPath {
+	return append(p[:len(p):len(p)], name)
+}
+
+// Branch returns the path of a branch of the multibranch project.
+// The branch name is encoded as the item name like the branch-api plugin does, e.g. feature/foo becomes feature%2Ffoo
+func (p ItemPath) Branch(branch string) ItemPath {
+	return p.Job(EncodeBranchName(branch))
+}
+
+// FullName returns the full name of the item, e.g. folder/job
+func (p ItemPath) FullName() string {
+	return strings.Join(p, "/")
+}
+
+// String returns the URL path of the item, e.g. /job/folder/job/feature%252Ffoo
+func (p ItemPath) String() string {
+	var builder strings.Builder
+	for _, name := range p {
+		builder.WriteString("/job/")
+		builder.WriteString(EscapePathSegment(name))
+	}
+	return builder.String()
+}
+
+// Run returns the URL path of a run, the last one if the id is less than 1
+func (p ItemPath) Run(id int) string {
+	if id < 1 {
+		return p.String() + "/lastBuild"
+	}
+	return fmt.Sprintf("%s/%d", p, id)
+}
+
+// EscapePathSegment escapes a string, then it could be a segment of the URL path safely
+func EscapePathSegment(segment string) string {
+	return url.PathEscape(segment)
+}
+
+// branchNameEscapes are the characters which are not allowed in the item names
+const branchNameEscapes = `%/\:?#*<>|"`
+
+// EncodeBranchName encodes a branch name as the item name, like the NameEncoder of the branch-api plugin
+func EncodeBranchName(branch string) string {
+	var builder strings.Builder
+	for _, c := range branch {
+		if strings.ContainsRune(branchNameEscapes, c) {
+			_, _ = fmt.Fprintf(&builder, "%%%02X", c)
+		} else {
+			builder.WriteRune(c)
+		}
+	}
+	return builder.String()
+}
+
+// DecodeBranchName decodes an item name to the branch name, it is the reverse of EncodeBranchName
+func DecodeBranchName(name string) string {
+	if branch, err := url.PathUnescape(name); err == nil {
+		return branch
+	}
+	return name
+}
+
+// ParseItemURL parses a Jenkins URL to the item path and the rest of the URL path, the views are skipped.
+// For example, http://localhost/view/all/job/a/job/feature%252Ffoo/12/console in the Jenkins http://localhost
+// is parsed as the item path [a feature%2Ffoo] and the rest /12/console.
+func ParseItemURL(jenkinsURL, itemURL string) (path ItemPath, rest string, err error) {
+	var base, target *url.URL
+	if base, err = url.Parse(jenkinsURL); err != nil {
+		return
+	}
+	if target, err = url.Parse(itemURL); err != nil {
+		return
+	}
+	if target.IsAbs() && target.Host != base.Host {
+		err = fmt.Errorf("%s is not a URL of the Jenkins %s", itemURL, jenkinsURL)
+		return
+	}
+
+	escapedPath := target.EscapedPath()
+	if target.IsAbs() {
+		basePath := strings.TrimSuffix(base.EscapedPath(), "/")
+		if !strings.HasPrefix(escapedPath, basePath+"/") && escapedPath != basePath {
+			err = fmt.Errorf("%s is not a URL of the Jenkins %s", itemURL, jenkinsURL)
+			return
+		}
+		escapedPath = strings.TrimPrefix(escapedPath, basePath)
+	}
+
+	path = ItemPath{}
+	segments := strings.Split(strings.Trim(escapedPath, "/"), "/")
+	i := 0
+	for ; i+1 < len(segments); i += 2 {
+		if segments[i] == "view" && len(path) == 0 {
+			continue
+		}
+		if segments[i] != "job" {
+			break
+		}
+
+		var name string
+		if name, err = url.PathUnescape(segments[i+1]); err != nil {
+			return
+		}
+		path = append(path, name)
+	}
+
+	if i < len(segments) && segments[i] != "" {
+		rest = "/" + strings.Join(segments[i:], "/")
+		if strings.HasSuffix(escapedPath, "/") {
+			rest += "/"
+		}
+	}
+	return
+}
+
+// ParseRunID parses the run ID from the rest of an item URL, e.g. /12/console
+func ParseRunID(rest string) (id int, ok bool) {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(rest, "/"), "/")
+	if val, err := strconv.Atoi(segment); err == nil && val > 0 {
+		id, ok = val, true
+	}
+	return
+}
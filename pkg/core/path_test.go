@@ -0,0 +1,68 @@
+package core
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("item path test", func() {
+	It("build the URL path", func() {
+		path := NewItemPath("folder a", "repo").Branch("feature/foo")
+		Expect(path.FullName()).To(Equal("folder a/repo/feature%2Ffoo"))
+		Expect(path.String()).To(Equal("/job/folder%20a/job/repo/job/feature%252Ffoo"))
+		Expect(path.Run(12)).To(Equal("/job/folder%20a/job/repo/job/feature%252Ffoo/12"))
+		Expect(path.Run(-1)).To(Equal("/job/folder%20a/job/repo/job/feature%252Ffoo/lastBuild"))
+		Expect(ItemPath{}.String()).To(BeEmpty())
+	})
+
+	It("do not share the names with the parent", func() {
+		parent := NewItemPath("a", "b", "c")[:2]
+		child := parent.Job("d")
+		Expect(parent.Job("e")).To(Equal(ItemPath{"a", "b", "e"}))
+		Expect(child).To(Equal(ItemPath{"a", "b", "d"}))
+	})
+
+	It("parse the full name", func() {
+		Expect(ParseFullName("/a/b/")).To(Equal(ItemPath{"a", "b"}))
+		Expect(ParseFullName("")).To(BeEmpty())
+	})
+
+	It("encode the branch name", func() {
+		Expect(EncodeBranchName("feature/a#1%")).To(Equal("feature%2Fa%231%25"))
+		Expect(DecodeBranchName("feature%2Fa%231%25")).To(Equal("feature/a#1%"))
+		Expect(EncodeBranchName("分支")).To(Equal("分支"))
+	})
+
+	DescribeTable("ParseItemURL",
+		func(jenkinsURL, itemURL string, expectedPath ItemPath, expectedRest string) {
+			path, rest, err := ParseItemURL(jenkinsURL, itemURL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(path).To(Equal(expectedPath))
+			Expect(rest).To(Equal(expectedRest))
+		},
+		Entry("job", "http://localhost", "http://localhost/job/a/", ItemPath{"a"}, ""),
+		Entry("with context path", "http://localhost/jenkins/", "http://localhost/jenkins/job/a/job/b/12/console",
+			ItemPath{"a", "b"}, "/12/console"),
+		Entry("branch", "http://localhost", "http://localhost/view/all/job/repo/job/feature%252Ffoo/lastBuild/",
+			ItemPath{"repo", "feature%2Ffoo"}, "/lastBuild/"),
+		Entry("relative path", "http://localhost", "/job/a%20b/api/json", ItemPath{"a b"}, "/api/json"),
+	)
+
+	It("round trip", func() {
+		path := NewItemPath("a #1", "50%").Branch("release/v1.0")
+		parsed, rest, err := ParseItemURL("http://localhost", "http://localhost"+path.Run(3))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed).To(Equal(path))
+		id, ok := ParseRunID(rest)
+		Expect(ok).To(BeTrue())
+		Expect(id).To(Equal(3))
+		Expect(DecodeBranchName(parsed[2])).To(Equal("release/v1.0"))
+	})
+
+	It("parse the URL of another Jenkins", func() {
+		_, _, err := ParseItemURL("http://localhost/jenkins", "http://localhost/other/job/a")
+		Expect(err).To(HaveOccurred())
+		_, _, err = ParseItemURL("http://localhost", "http://remote/job/a")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,180 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/verystar/jenkins-client/pkg/util"
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is a named Jenkins server, it is compatible with the server setting of jcli
+type Profile struct {
+	Name               string `yaml:"name" json:"name"`
+	URL                string `yaml:"url" json:"url"`
+	UserName           string `yaml:"username" json:"username"`
+	Token              string `yaml:"token" json:"token"`
+	Proxy              string `yaml:"proxy" json:"proxy"`
+	ProxyAuth          string `yaml:"proxyAuth" json:"proxyAuth"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify" json:"insecureSkipVerify"`
+	Description        string `yaml:"description" json:"description"`
+
+	// TokenEnv is the environment variable which takes the place of Token if it exists
+	TokenEnv string `yaml:"tokenEnv" json:"tokenEnv"`
+	// TokenFile is a file which contains the token, it takes the place of Token
+	TokenFile string `yaml:"tokenFile" json:"tokenFile"`
+}
+
+// RegistryConfig is the config file of the profiles, it is compatible with ~/.jenkins-cli.yaml of jcli
+type RegistryConfig struct {
+	Current        string    `yaml:"current" json:"current"`
+	JenkinsServers []Profile `yaml:"jenkins_servers" json:"jenkins_servers"`
+}
+
+// Registry holds the named profiles of many Jenkins controllers, it is safe for concurrent use.
+// The JenkinsCore of a profile is built once, the clients created from it share the HTTP client.
+type Registry struct {
+	lock   sync.Mutex
+	config RegistryConfig
+	opts   []Option
+	cores  map[string]*JenkinsCore
+}
+
+// DefaultRegistryConfigPath returns the path of the jcli config file
+func DefaultRegistryConfigPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".jenkins-cli.yaml")
+}
+
+// LoadRegistry loads the profiles from a YAML or JSON file,
+// the options are applied to all the JenkinsCore after the profile settings
+func LoadRegistry(configPath string, opts ...Option) (registry *Registry, err error) {
+	var data []byte
+	if data, err = os.ReadFile(configPath); err != nil {
+		err = fmt.Errorf("failed to read the config file, error is %v", err)
+		return
+	}
+
+	// JSON is a subset of YAML
+	config := RegistryConfig{}
+	if err = yaml.Unmarshal(data, &config); err != nil {
+		err = fmt.Errorf("failed to parse the config file %s, error is %v", configPath, err)
+		return
+	}
+	registry = NewRegistry(config, opts...)
+	return
+}
+
+// NewRegistry creates a Registry with the profiles
+func NewRegistry(config RegistryConfig, opts ...Option) *Registry {
+	return &Registry{
+		config: config,
+		opts:   opts,
+		cores:  map[string]*JenkinsCore{},
+	}
+}
+
+// Names returns the sorted names of all the profiles
+func (r *Registry) Names() (names []string) {
+	for _, profile := range r.config.JenkinsServers {
+		names = append(names, profile.Name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// Current returns the name of the current profile
+func (r *Registry) Current() string {
+	return r.config.Current
+}
+
+// Profile returns the profile by name
+func (r *Registry) Profile(name string) (profile Profile, ok bool) {
+	for _, profile = range r.config.JenkinsServers {
+		if profile.Name == name {
+			ok = true
+			return
+		}
+	}
+	profile = Profile{}
+	return
+}
+
+// Get returns the JenkinsCore of a profile, it returns the current one if the name is empty
+func (r *Registry) Get(name string) (jenkins *JenkinsCore, err error) {
+	if name == "" {
+		name = r.config.Current
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if jenkins = r.cores[name]; jenkins != nil {
+		return
+	}
+
+	profile, ok := r.Profile(name)
+	if !ok {
+		err = fmt.Errorf("cannot find the Jenkins profile %q", name)
+		return
+	}
+
+	var opts []Option
+	if opts, err = profile.options(); err != nil {
+		err = fmt.Errorf("invalid Jenkins profile %q, error is %v", name, err)
+		return
+	}
+	if jenkins, err = NewJenkinsCore(profile.URL, append(opts, r.opts...)...); err != nil {
+		err = fmt.Errorf("invalid Jenkins profile %q, error is %v", name, err)
+		return
+	}
+	r.cores[name] = jenkins
+	return
+}
+
+// GetClient returns the Client of a profile
+func (r *Registry) GetClient(name string) (client *Client, err error) {
+	var jenkins *JenkinsCore
+	if jenkins, err = r.Get(name); err == nil {
+		client = NewClient(jenkins)
+	}
+	return
+}
+
+// GetToken returns the token of the profile.
+// The token is taken from TokenEnv, TokenFile and Token in order.
+func (p Profile) GetToken() (token string, err error) {
+	token = p.Token
+	if p.TokenFile != "" {
+		var data []byte
+		if data, err = os.ReadFile(p.TokenFile); err != nil {
+			err = fmt.Errorf("failed to read the token file, error is %v", err)
+			return
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if p.TokenEnv != "" {
+		token = util.GetEnvOrDefault(p.TokenEnv, token)
+	}
+	return
+}
+
+func (p Profile) options() (opts []Option, err error) {
+	var token string
+	if token, err = p.GetToken(); err != nil {
+		return
+	}
+	if p.UserName != "" && token != "" {
+		opts = append(opts, WithBasicAuth(p.UserName, token))
+	}
+	if p.Proxy != "" {
+		opts = append(opts, WithProxy(p.Proxy, p.ProxyAuth))
+	}
+	if p.InsecureSkipVerify {
+		opts = append(opts, WithInsecureSkipVerify())
+	}
+	return
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("registry test", func() {
+	var (
+		configDir string
+		registry  *Registry
+		err       error
+	)
+
+	BeforeEach(func() {
+		configDir = GinkgoT().TempDir()
+		tokenFile := filepath.Join(configDir, "token")
+		Expect(os.WriteFile(tokenFile, []byte("file-token\n"), 0600)).To(Succeed())
+
+		config := `current: dev
+language: ""
+jenkins_servers:
+- name: dev
+  url: http://localhost:8080/jenkins
+  username: admin
+  token: inline-token
+  proxy: ""
+  proxyAuth: ""
+  insecureSkipVerify: true
+  description: ""
+- name: prod
+  url: https://ci.example.com
+  username: admin
+  tokenFile: ` + tokenFile + `
+  tokenEnv: FAKE_JENKINS_TOKEN
+- name: invalid
+  url: https://ci.example.com
+  proxy: "htp//typo"
+`
+		configPath := filepath.Join(configDir, ".jenkins-cli.yaml")
+		Expect(os.WriteFile(configPath, []byte(config), 0600)).To(Succeed())
+		registry, err = LoadRegistry(configPath)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("load the jcli config", func() {
+		Expect(registry.Names()).To(Equal([]string{"dev", "invalid", "prod"}))
+		Expect(registry.Current()).To(Equal("dev"))
+
+		jenkins, err := registry.Get("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jenkins.URL).To(Equal("http://localhost:8080/jenkins"))
+		Expect(jenkins.Token).To(Equal("inline-token"))
+		Expect(jenkins.InsecureSkipVerify).To(BeTrue())
+
+		again, err := registry.Get("dev")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again).To(BeIdenticalTo(jenkins))
+	})
+
+	It("take the token from the file and the environment", func() {
+		jenkins, err := registry.Get("prod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jenkins.Token).To(Equal("file-token"))
+
+		GinkgoT().Setenv("FAKE_JENKINS_TOKEN", "env-token")
+		profile, ok := registry.Profile("prod")
+		Expect(ok).To(BeTrue())
+		Expect(profile.GetToken()).To(Equal("env-token"))
+	})
+
+	It("with invalid profiles", func() {
+		_, err := registry.Get("none")
+		Expect(err).To(HaveOccurred())
+
+		_, err = registry.GetClient("invalid")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid"))
+	})
+
+	It("load a JSON config", func() {
+		configPath := filepath.Join(configDir, "config.json")
+		Expect(os.WriteFile(configPath, []byte(`{"current":"dev","jenkins_servers":[{"name":"dev","url":"http://localhost"}]}`), 0600)).To(Succeed())
+
+		registry, err = LoadRegistry(configPath)
+		Expect(err).NotTo(HaveOccurred())
+		client, err := registry.GetClient("dev")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.URL).To(Equal("http://localhost"))
+
+		_, err = LoadRegistry(filepath.Join(configDir, "none.yaml"))
+		Expect(err).To(HaveOccurred())
+	})
+})
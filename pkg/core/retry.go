@@ -0,0 +1,171 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultRetryInitialBackoff = 500 * time.Millisecond
+	defaultRetryMaxBackoff     = 30 * time.Second
+)
+
+// RetryPolicy controls how a failed request is retried.
+// The requests with GET, HEAD and OPTIONS methods are retried automatically,
+// the other requests are retried only if RequestBuilder.WithRetry was called.
+// Only the network errors, the timeouts and the RetryableStatusCodes are retried.
+// The zero value means no retry.
+type RetryPolicy struct {
+	// MaxAttempts is the max number of the attempts including the first one, no retry if it is less than 2
+	MaxAttempts int
+	// InitialBackoff is the wait time before the first retry, it doubles on each retry
+	InitialBackoff time.Duration
+	// MaxBackoff is the upper limit of the wait time, including the one comes from the Retry-After header
+	MaxBackoff time.Duration
+	// RetryableStatusCodes are the status codes worth a retry, it is 429, 502, 503 and 504 if it is nil
+	RetryableStatusCodes []int
+	// DisableJitter disables the randomization of the wait time
+	DisableJitter bool
+}
+
+// DefaultRetryPolicy returns a retry policy which fits the restarting of Jenkins
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: defaultRetryInitialBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+	}
+}
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// shouldRetry checks if the request should be sent again after the given attempt
+func (p RetryPolicy) shouldRetry(ctx context.Context, attempt int, response *http.Response, err error) bool {
+	if attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return isNetworkError(err)
+	}
+
+	codes := p.RetryableStatusCodes
+	if codes == nil {
+		codes = defaultRetryableStatusCodes
+	}
+	for _, code := range codes {
+		if response.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// isNetworkError returns true for the connection errors and timeouts, such as connection refused during the
+// restarting of Jenkins. The other errors are permanent, e.g. the errors of the Authenticator or the invalid settings.
+func isNetworkError(err error) bool {
+	// url.Error is a net.Error itself, check the error it wraps
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// backoff returns the wait time before the next attempt
+func (p RetryPolicy) backoff(attempt int, response *http.Response) (delay time.Duration) {
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+
+	if retryAfter, ok := parseRetryAfter(response); ok {
+		delay = retryAfter
+	} else {
+		delay = p.InitialBackoff
+		if delay <= 0 {
+			delay = defaultRetryInitialBackoff
+		}
+		for i := 1; i < attempt && delay < maxBackoff; i++ {
+			delay *= 2
+		}
+		if delay > maxBackoff {
+			delay = maxBackoff
+		}
+		if !p.DisableJitter && delay > 1 {
+			// keep the half of the delay at least
+			delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)))
+		}
+	}
+
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return
+}
+
+// parseRetryAfter parses the Retry-After header, it could be seconds or a HTTP date
+func parseRetryAfter(response *http.Response) (delay time.Duration, ok bool) {
+	if response == nil {
+		return
+	}
+	value := response.Header.Get("Retry-After")
+	if value == "" {
+		return
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay = time.Until(date); delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return
+}
+
+// isIdempotentMethod returns true if the request is safe to be sent again
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// discardResponse drains and closes the body, so the connection could be reused
+func discardResponse(response *http.Response) {
+	if response != nil && response.Body != nil {
+		_, _ = io.Copy(io.Discard, response.Body)
+		_ = response.Body.Close()
+	}
+}
+
+// sleepContext waits for the given duration unless the context is done
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,171 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("retry test", func() {
+	var (
+		ctrl         *gomock.Controller
+		jenkinsCore  JenkinsCore
+		roundTripper *mhttp.MockRoundTripper
+		statusCodes  []int
+		calls        int
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		jenkinsCore = JenkinsCore{}
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		jenkinsCore.RoundTripper = roundTripper
+		jenkinsCore.URL = "http://localhost"
+		jenkinsCore.RetryPolicy = RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     10 * time.Millisecond,
+		}
+		calls = 0
+		statusCodes = nil
+
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/crumbIssuer/api/json" {
+				return &http.Response{
+					StatusCode: 404,
+					Request:    req,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+				}, nil
+			}
+
+			code := statusCodes[calls]
+			calls++
+			switch code {
+			case 0:
+				return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+			case -1:
+				return nil, errors.New("invalid proxy")
+			}
+			return &http.Response{
+				StatusCode: code,
+				Header:     http.Header{},
+				Request:    req,
+				Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+			}, nil
+		}).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("retry a GET request until success", func() {
+		statusCodes = []int{503, 0, 200}
+		Expect(NewRequest("/fake", &jenkinsCore).Do()).To(Succeed())
+		Expect(calls).To(Equal(3))
+	})
+
+	It("stop retrying after the max attempts", func() {
+		statusCodes = []int{503, 503, 503, 200}
+		err := NewRequest("/fake", &jenkinsCore).Do()
+		Expect(IsServerError(err)).To(BeTrue())
+		Expect(calls).To(Equal(3))
+	})
+
+	It("do not retry the status code which is not retryable", func() {
+		statusCodes = []int{500, 200}
+		Expect(NewRequest("/fake", &jenkinsCore).Do()).NotTo(Succeed())
+		Expect(calls).To(Equal(1))
+	})
+
+	It("do not retry without a retry policy", func() {
+		jenkinsCore.RetryPolicy = RetryPolicy{}
+		statusCodes = []int{503, 200}
+		Expect(NewRequest("/fake", &jenkinsCore).Do()).NotTo(Succeed())
+		Expect(calls).To(Equal(1))
+	})
+
+	It("do not retry a POST request by default", func() {
+		statusCodes = []int{503, 200}
+		Expect(NewRequest("/fake", &jenkinsCore).WithPostMethod().Do()).NotTo(Succeed())
+		Expect(calls).To(Equal(1))
+	})
+
+	It("retry a POST request with the payload when it is opt-in", func() {
+		statusCodes = []int{503, 200}
+		request := NewRequest("/fake", &jenkinsCore).WithPostMethod().WithRetry().
+			WithPayload(bytes.NewBufferString("payload"))
+		Expect(request.Do()).To(Succeed())
+		Expect(calls).To(Equal(2))
+	})
+
+	It("do not retry the permanent errors", func() {
+		jenkinsCore.Authenticator = failedAuth{}
+		statusCodes = []int{200}
+		Expect(NewRequest("/fake", &jenkinsCore).Do()).To(MatchError("no token"))
+		Expect(calls).To(Equal(0))
+
+		jenkinsCore.Authenticator = nil
+		statusCodes = []int{-1, 200}
+		Expect(NewRequest("/fake", &jenkinsCore).Do()).To(MatchError(ContainSubstring("invalid proxy")))
+		Expect(calls).To(Equal(1))
+	})
+
+	It("stop retrying when the context is done", func() {
+		jenkinsCore.RetryPolicy.InitialBackoff = time.Hour
+		jenkinsCore.RetryPolicy.MaxBackoff = time.Hour
+		statusCodes = []int{503, 200}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := NewRequestWithContext(ctx, "/fake", &jenkinsCore).Do()
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		Expect(calls).To(Equal(1))
+	})
+})
+
+// failedAuth is an Authenticator which always fails
+type failedAuth struct{}
+
+func (failedAuth) Authenticate(*JenkinsCore, *http.Request) error {
+	return errors.New("no token")
+}
+
+var _ = Describe("RetryPolicy", func() {
+	It("exponential backoff without jitter", func() {
+		policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, DisableJitter: true}
+		Expect(policy.backoff(1, nil)).To(Equal(time.Second))
+		Expect(policy.backoff(2, nil)).To(Equal(2 * time.Second))
+		Expect(policy.backoff(3, nil)).To(Equal(4 * time.Second))
+		Expect(policy.backoff(4, nil)).To(Equal(5 * time.Second))
+	})
+
+	It("exponential backoff with jitter", func() {
+		policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+		delay := policy.backoff(2, nil)
+		Expect(delay).To(BeNumerically(">=", time.Second))
+		Expect(delay).To(BeNumerically("<=", 2*time.Second))
+	})
+
+	It("honour the Retry-After header", func() {
+		policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: time.Minute}
+		response := &http.Response{Header: http.Header{"Retry-After": {"7"}}}
+		Expect(policy.backoff(1, response)).To(Equal(7 * time.Second))
+
+		response.Header.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+		Expect(policy.backoff(1, response)).To(Equal(time.Duration(0)))
+
+		response.Header.Set("Retry-After", "3600")
+		Expect(policy.backoff(1, response)).To(Equal(time.Minute))
+	})
+})
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateLimiter limits the rate of the requests, Wait blocks until a request is allowed.
+// The Limiter from golang.org/x/time/rate satisfies this interface.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// Throttle limits the rate and the concurrency of the requests sent to a Jenkins.
+// A Throttle is safe for concurrent use, share one Throttle across all the clients of the same Jenkins.
+type Throttle struct {
+	limiter  RateLimiter
+	inFlight chan struct{}
+
+	requests  atomic.Int64
+	waits     atomic.Int64
+	waitNanos atomic.Int64
+	running   atomic.Int64
+}
+
+// ThrottleStats is the statistics of a Throttle
+type ThrottleStats struct {
+	// Requests is the number of requests passed the Throttle
+	Requests int64
+	// Waits is the number of requests which had to wait
+	Waits int64
+	// WaitTime is the total time spent on waiting
+	WaitTime time.Duration
+	// InFlight is the number of requests which are running
+	InFlight int64
+}
+
+// NewThrottle creates a Throttle, the limiter could be nil and zero maxInFlight means no concurrency limit
+func NewThrottle(limiter RateLimiter, maxInFlight int) *Throttle {
+	throttle := &Throttle{limiter: limiter}
+	if maxInFlight > 0 {
+		throttle.inFlight = make(chan struct{}, maxInFlight)
+	}
+	return throttle
+}
+
+// Stats returns the statistics of this Throttle
+func (t *Throttle) Stats() ThrottleStats {
+	return ThrottleStats{
+		Requests: t.requests.Load(),
+		Waits:    t.waits.Load(),
+		WaitTime: time.Duration(t.waitNanos.Load()),
+		InFlight: t.running.Load(),
+	}
+}
+
+// acquire blocks until the request is allowed, call release once the request is done
+func (t *Throttle) acquire(ctx context.Context) (release func(), err error) {
+	begin := time.Now()
+	if t.limiter != nil {
+		if err = t.limiter.Wait(ctx); err != nil {
+			return
+		}
+	}
+
+	if t.inFlight != nil {
+		select {
+		case t.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		}
+	}
+
+	// a tiny wait time comes from the locks instead of the limits
+	if wait := time.Since(begin); wait > time.Millisecond {
+		t.waits.Add(1)
+		t.waitNanos.Add(int64(wait))
+	}
+	t.requests.Add(1)
+	t.running.Add(1)
+
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			t.running.Add(-1)
+			if t.inFlight != nil {
+				<-t.inFlight
+			}
+		})
+	}
+	return
+}
+
+// releaseOnClose releases the Throttle when the response body is closed
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close closes the body and releases the Throttle
+func (r *releaseOnClose) Close() error {
+	defer r.release()
+	return r.ReadCloser.Close()
+}
+
+// tokenBucket is a simple token bucket rate limiter
+type tokenBucket struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a token bucket rate limiter which allows ratePerSecond requests with the burst size
+func NewTokenBucket(ratePerSecond float64, burst int) RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   ratePerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait takes a token, it blocks until a token is available or the context is done
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	if b.rate <= 0 {
+		return nil
+	}
+
+	b.lock.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	// reserve a token even if the bucket is empty, then wait for it
+	b.tokens--
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.lock.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, delay); err != nil {
+		// give the reserved token back
+		b.lock.Lock()
+		b.tokens++
+		b.lock.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Throttle test", func() {
+	var (
+		ctrl         *gomock.Controller
+		jenkinsCore  JenkinsCore
+		roundTripper *mhttp.MockRoundTripper
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		jenkinsCore = JenkinsCore{}
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		jenkinsCore.RoundTripper = roundTripper
+		jenkinsCore.URL = "http://localhost"
+
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 200,
+				Request:    req,
+				Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+			}, nil
+		}).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("share the Throttle across the clients", func() {
+		jenkinsCore.Throttle = NewThrottle(NewTokenBucket(1000, 10), 2)
+		copied := jenkinsCore
+
+		Expect(NewRequest("/fake", &jenkinsCore).Do()).To(Succeed())
+		Expect(NewRequest("/fake", &copied).Do()).To(Succeed())
+
+		stats := jenkinsCore.Throttle.Stats()
+		Expect(stats.Requests).To(Equal(int64(2)))
+		Expect(stats.InFlight).To(Equal(int64(0)))
+	})
+
+	It("hold the slot until the response body is closed", func() {
+		jenkinsCore.Throttle = NewThrottle(nil, 1)
+
+		response, err := jenkinsCore.RequestWithResponse(http.MethodGet, "/fake", nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jenkinsCore.Throttle.Stats().InFlight).To(Equal(int64(1)))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, _, err = jenkinsCore.RequestContext(ctx, http.MethodGet, "/fake", nil, nil)
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+
+		Expect(response.Body.Close()).To(Succeed())
+		Expect(jenkinsCore.Throttle.Stats().InFlight).To(Equal(int64(0)))
+		Expect(NewRequest("/fake", &jenkinsCore).Do()).To(Succeed())
+		Expect(jenkinsCore.Throttle.Stats().Waits).To(Equal(int64(0)))
+	})
+
+	It("record the wait time", func() {
+		jenkinsCore.Throttle = NewThrottle(NewTokenBucket(50, 1), 0)
+
+		for i := 0; i < 3; i++ {
+			Expect(NewRequest("/fake", &jenkinsCore).Do()).To(Succeed())
+		}
+		stats := jenkinsCore.Throttle.Stats()
+		Expect(stats.Requests).To(Equal(int64(3)))
+		Expect(stats.Waits).To(Equal(int64(2)))
+		Expect(stats.WaitTime).To(BeNumerically(">=", 30*time.Millisecond))
+	})
+})
+
+var _ = Describe("token bucket", func() {
+	It("allow the burst without waiting", func() {
+		limiter := NewTokenBucket(1, 3)
+		begin := time.Now()
+		for i := 0; i < 3; i++ {
+			Expect(limiter.Wait(context.Background())).To(Succeed())
+		}
+		Expect(time.Since(begin)).To(BeNumerically("<", 100*time.Millisecond))
+	})
+
+	It("stop waiting when the context is done", func() {
+		limiter := NewTokenBucket(0.1, 1)
+		Expect(limiter.Wait(context.Background())).To(Succeed())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		Expect(errors.Is(limiter.Wait(ctx), context.DeadlineExceeded)).To(BeTrue())
+	})
+})
@@ -0,0 +1,171 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// RequestInfo describes a HTTP request which is going to be sent to Jenkins
+type RequestInfo struct {
+	Method string
+	URL    string
+	// Header is a copy of the request header, the credentials are redacted
+	Header http.Header
+	// ContentLength is the size of the request body, -1 means unknown
+	ContentLength int64
+	// Crumb is true if the request carries a crumb
+	Crumb bool
+}
+
+// ResponseInfo describes the result of a HTTP request
+type ResponseInfo struct {
+	RequestInfo
+	// StatusCode is zero if there is an error
+	StatusCode int
+	// ContentLength is the size of the response body, -1 means unknown
+	ContentLength int64
+	Latency       time.Duration
+	Err           error
+}
+
+// Tracer has the hooks of the HTTP requests, both of the hooks are optional
+type Tracer struct {
+	OnRequest  func(ctx context.Context, info *RequestInfo)
+	OnResponse func(ctx context.Context, info *ResponseInfo)
+}
+
+// NewSlogTracer returns a Tracer which emits a record for each HTTP request in the level
+func NewSlogTracer(logger *slog.Logger, level slog.Level) *Tracer {
+	return &Tracer{
+		OnResponse: func(ctx context.Context, info *ResponseInfo) {
+			logger.LogAttrs(ctx, level, "HTTP request", info.attrs()...)
+		},
+	}
+}
+
+func (info *ResponseInfo) attrs() []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("method", info.Method),
+		slog.String("URL", info.URL),
+		slog.Int("status", info.StatusCode),
+		slog.Duration("latency", info.Latency),
+		slog.Int64("requestBytes", info.RequestInfo.ContentLength),
+		slog.Int64("responseBytes", info.ContentLength),
+		slog.Bool("crumb", info.Crumb),
+	}
+	if auth := info.Header.Get("Authorization"); auth != "" {
+		attrs = append(attrs, slog.String("authorization", auth))
+	}
+	if info.Err != nil {
+		attrs = append(attrs, slog.Any("error", info.Err))
+	}
+	return attrs
+}
+
+// RedactHeader returns a copy of the header without the credentials, the auth scheme is kept
+func RedactHeader(header http.Header) http.Header {
+	result := header.Clone()
+	for key, values := range result {
+		if !isSensitiveHeader(key) {
+			continue
+		}
+		for i, val := range values {
+			if scheme, _, ok := strings.Cut(val, " "); ok && strings.HasSuffix(key, "Authorization") {
+				values[i] = scheme + " " + redacted
+			} else {
+				values[i] = redacted
+			}
+		}
+	}
+	return result
+}
+
+func isSensitiveHeader(key string) bool {
+	switch http.CanonicalHeaderKey(key) {
+	case "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie":
+		return true
+	}
+	return strings.Contains(strings.ToLower(key), "crumb")
+}
+
+// traceRequest calls the OnRequest hook, and dumps the request to Output in the debug mode
+func (j *JenkinsCore) traceRequest(req *http.Request) *ResponseInfo {
+	info := &ResponseInfo{
+		RequestInfo: RequestInfo{
+			Method:        req.Method,
+			URL:           req.URL.String(),
+			Header:        RedactHeader(req.Header),
+			ContentLength: req.ContentLength,
+		},
+	}
+	for key := range req.Header {
+		if strings.Contains(strings.ToLower(key), "crumb") {
+			info.Crumb = true
+		}
+	}
+	if req.Body == nil {
+		info.RequestInfo.ContentLength = 0
+	}
+
+	if j.Tracer != nil && j.Tracer.OnRequest != nil {
+		j.Tracer.OnRequest(req.Context(), &info.RequestInfo)
+	}
+	if j.Debug && j.Output != nil {
+		j.dumpRequest(req, info.Header)
+	}
+	return info
+}
+
+// traceResponse calls the OnResponse hook, and dumps the response to Output in the debug mode
+func (j *JenkinsCore) traceResponse(req *http.Request, info *ResponseInfo, response *http.Response, err error,
+	latency time.Duration) {
+	info.Latency = latency
+	info.Err = err
+	info.ContentLength = -1
+	if response != nil {
+		info.StatusCode = response.StatusCode
+		info.ContentLength = response.ContentLength
+	}
+
+	j.logger().LogAttrs(req.Context(), slog.LevelDebug, "HTTP request", info.attrs()...)
+	if j.Tracer != nil && j.Tracer.OnResponse != nil {
+		j.Tracer.OnResponse(req.Context(), info)
+	}
+	if j.Debug && j.Output != nil && response != nil {
+		j.dumpResponse(response)
+	}
+}
+
+func (j *JenkinsCore) dumpRequest(req *http.Request, header http.Header) {
+	dumpReq := req.Clone(req.Context())
+	dumpReq.Header = header
+	dumpBody := false
+	if req.GetBody != nil {
+		// the body of the original request must be kept
+		if body, err := req.GetBody(); err == nil {
+			dumpReq.Body = body
+			dumpBody = true
+		}
+	}
+	if data, err := httputil.DumpRequestOut(dumpReq, dumpBody); err == nil {
+		_, _ = fmt.Fprintf(j.Output, "%s\n", data)
+	}
+}
+
+func (j *JenkinsCore) dumpResponse(response *http.Response) {
+	// the body is read into memory, then it is still readable after dumping
+	dumpResp := *response
+	dumpResp.Header = RedactHeader(response.Header)
+	data, err := httputil.DumpResponse(&dumpResp, true)
+	response.Body = dumpResp.Body
+	if err == nil {
+		_, _ = fmt.Fprintf(j.Output, "%s\n", data)
+	}
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("trace test", func() {
+	var (
+		ctrl         *gomock.Controller
+		jenkinsCore  JenkinsCore
+		roundTripper *mhttp.MockRoundTripper
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		jenkinsCore = JenkinsCore{}
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		jenkinsCore.RoundTripper = roundTripper
+		jenkinsCore.URL = "http://localhost"
+		jenkinsCore.UserName = "admin"
+		jenkinsCore.Token = "secret-token"
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectRequest := func(statusCode int, body string) {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode:    statusCode,
+				Proto:         "HTTP/1.1",
+				ProtoMajor:    1,
+				ProtoMinor:    1,
+				Request:       req,
+				Header:        http.Header{"Set-Cookie": {"JSESSIONID.fake=session"}},
+				ContentLength: int64(len(body)),
+				Body:          ioutil.NopCloser(bytes.NewBufferString(body)),
+			}, nil
+		})
+	}
+
+	It("call the hooks with the redacted header", func() {
+		var (
+			requests  []*RequestInfo
+			responses []*ResponseInfo
+		)
+		jenkinsCore.Tracer = &Tracer{
+			OnRequest: func(ctx context.Context, info *RequestInfo) {
+				requests = append(requests, info)
+			},
+			OnResponse: func(ctx context.Context, info *ResponseInfo) {
+				responses = append(responses, info)
+			},
+		}
+		expectRequest(http.StatusInternalServerError, "error")
+
+		_, _, err := jenkinsCore.Request(http.MethodGet, "/fake", map[string]string{"Jenkins-Crumb": "fake"}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Method).To(Equal(http.MethodGet))
+		Expect(requests[0].URL).To(Equal("http://localhost/fake"))
+		Expect(requests[0].Header.Get("Authorization")).To(Equal("Basic [REDACTED]"))
+		Expect(requests[0].Header.Get("Jenkins-Crumb")).To(Equal("[REDACTED]"))
+		Expect(requests[0].Crumb).To(BeTrue())
+
+		Expect(responses).To(HaveLen(1))
+		Expect(responses[0].StatusCode).To(Equal(http.StatusInternalServerError))
+		Expect(responses[0].ContentLength).To(Equal(int64(5)))
+		Expect(responses[0].Latency).To(BeNumerically(">", 0))
+	})
+
+	It("emit the slog records", func() {
+		buf := &bytes.Buffer{}
+		jenkinsCore.Tracer = NewSlogTracer(slog.New(slog.NewTextHandler(buf, nil)), slog.LevelInfo)
+		expectRequest(http.StatusOK, "{}")
+
+		_, _, err := jenkinsCore.Request(http.MethodGet, "/fake", nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(ContainSubstring("status=200"))
+		Expect(buf.String()).To(ContainSubstring("latency="))
+		Expect(buf.String()).To(ContainSubstring(`authorization="Basic [REDACTED]"`))
+		Expect(buf.String()).NotTo(ContainSubstring("secret-token"))
+	})
+
+	It("dump the request and response in the debug mode", func() {
+		buf := &bytes.Buffer{}
+		jenkinsCore.Debug = true
+		jenkinsCore.Output = buf
+		expectRequest(http.StatusOK, "fake-response")
+		jenkinsCore.getClientCache().setCrumb(jenkinsCore.URL, nil)
+
+		_, data, err := jenkinsCore.Request(http.MethodPost, "/fake", nil, strings.NewReader("fake-payload"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("fake-response"))
+
+		output := buf.String()
+		Expect(output).To(ContainSubstring("POST /fake HTTP/1.1"))
+		Expect(output).To(ContainSubstring("fake-payload"))
+		Expect(output).To(ContainSubstring("HTTP/1.1 200 OK"))
+		Expect(output).To(ContainSubstring("fake-response"))
+		Expect(output).To(ContainSubstring("Set-Cookie: [REDACTED]"))
+		Expect(output).NotTo(ContainSubstring("secret-token"))
+	})
+
+	It("redact the header", func() {
+		header := RedactHeader(http.Header{
+			"Authorization":       {"Bearer token"},
+			"Proxy-Authorization": {"token"},
+			"Accept":              {"*/*"},
+		})
+		Expect(header.Get("Authorization")).To(Equal("Bearer [REDACTED]"))
+		Expect(header.Get("Proxy-Authorization")).To(Equal("[REDACTED]"))
+		Expect(header.Get("Accept")).To(Equal("*/*"))
+	})
+})
@@ -0,0 +1,344 @@
+package core
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+const (
+	defaultTimeout             = 15 * time.Second
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 10
+	defaultIdleConnTimeout     = 90 * time.Second
+)
+
+// DefaultUserAgent is the User-Agent header of the requests if there is no one in JenkinsCore
+const DefaultUserAgent = "jcli; v1.0.0"
+
+// TransportConfig is the connection pool setting of the HTTP transport.
+// The zero value of each field means using the default value.
+type TransportConfig struct {
+	// MaxIdleConns controls the maximum number of idle connections across all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost controls the maximum idle connections to keep per-host
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits the total number of connections per host, zero means no limit
+	MaxConnsPerHost int
+	// IdleConnTimeout is the maximum amount of time an idle connection will remain idle before closing itself
+	IdleConnTimeout time.Duration
+	// DisableHTTP2 prevents the transport from trying HTTP/2
+	DisableHTTP2 bool
+}
+
+// transportKey holds all the settings which the HTTP transport depends on, it must be comparable
+type transportKey struct {
+	insecureSkipVerify bool
+	proxy              string
+	proxyAuth          string
+	noProxy            string
+	proxyFromEnv       bool
+	caCertFile         string
+	config             TransportConfig
+	tlsAuth            tlsAuthIdentity
+}
+
+// tlsAuthIdentity identifies a TLSAuthenticator, it is comparable even if the TLSAuthenticator is not
+type tlsAuthIdentity struct {
+	typ     reflect.Type
+	pointer uintptr
+	value   string
+}
+
+// identifyTLSAuth identifies a pointer by its address, or other values by their content
+func identifyTLSAuth(auth TLSAuthenticator) (id tlsAuthIdentity) {
+	if auth == nil {
+		return
+	}
+	v := reflect.ValueOf(auth)
+	id.typ = v.Type()
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		id.pointer = v.Pointer()
+	default:
+		id.value = fmt.Sprintf("%#v", auth)
+	}
+	return
+}
+
+// clientKey holds all the settings which the HTTP client depends on
+type clientKey struct {
+	transport transportKey
+	timeout   time.Duration
+}
+
+// cachedTransport is a transport built from the settings, or the error of the invalid settings
+type cachedTransport struct {
+	transport http.RoundTripper
+	err       error
+	// tlsAuth keeps the TLSAuthenticator alive, then its address can not be taken by another one
+	tlsAuth TLSAuthenticator
+}
+
+// clientCache holds the lazily built HTTP clients which are safe for concurrent use.
+// The copies of a JenkinsCore share it, each group of the transport settings has its own transport,
+// and the clients with different timeouts share the transport and its connection pool.
+type clientCache struct {
+	lock       sync.Mutex
+	transports map[transportKey]*cachedTransport
+	clients    map[clientKey]*http.Client
+	jar        http.CookieJar
+
+	crumb  crumbCache
+	logins loginSessions
+}
+
+// clientCacheLock protects the lazy initialization of JenkinsCore.clientCache
+var clientCacheLock sync.Mutex
+
+func (j *JenkinsCore) getClientCache() *clientCache {
+	clientCacheLock.Lock()
+	defer clientCacheLock.Unlock()
+	if j.clientCache == nil {
+		// cookiejar.New never returns an error
+		jar, _ := cookiejar.New(nil)
+		j.clientCache = &clientCache{
+			transports: map[transportKey]*cachedTransport{},
+			clients:    map[clientKey]*http.Client{},
+			jar:        jar,
+		}
+	}
+	return j.clientCache
+}
+
+func (j *JenkinsCore) transportKey() (key transportKey, tlsAuth TLSAuthenticator) {
+	tlsAuth, _ = j.Authenticator.(TLSAuthenticator)
+	key = transportKey{
+		insecureSkipVerify: j.InsecureSkipVerify,
+		proxy:              j.Proxy,
+		proxyAuth:          j.ProxyAuth,
+		noProxy:            j.NoProxy,
+		proxyFromEnv:       j.ProxyFromEnvironment,
+		caCertFile:         j.CACertFile,
+		config:             j.TransportConfig,
+		tlsAuth:            identifyTLSAuth(tlsAuth),
+	}
+	return
+}
+
+// getPooledClient returns the cached client of the settings of this JenkinsCore
+func (j *JenkinsCore) getPooledClient() (*http.Client, error) {
+	key, tlsAuth := j.transportKey()
+	return j.getClientCache().get(key, tlsAuth, j.timeout())
+}
+
+// get returns the cached client, or builds a new one if there is no client of the settings.
+// The returned client is never nil, all of its requests fail with the error if the settings are invalid.
+func (c *clientCache) get(key transportKey, tlsAuth TLSAuthenticator, timeout time.Duration) (*http.Client, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	cached, ok := c.transports[key]
+	if !ok {
+		cached = &cachedTransport{tlsAuth: tlsAuth}
+		var tr *http.Transport
+		if tr, cached.err = newTransport(key, tlsAuth); cached.err == nil {
+			cached.transport = tr
+		} else {
+			Logger.Error("invalid HTTP transport settings", "error", cached.err)
+			cached.transport = errorRoundTripper{err: cached.err}
+		}
+		c.transports[key] = cached
+	}
+
+	clientKey := clientKey{transport: key, timeout: timeout}
+	client, ok := c.clients[clientKey]
+	if !ok {
+		client = &http.Client{
+			Transport: cached.transport,
+			Timeout:   timeout,
+			Jar:       c.jar,
+		}
+		c.clients[clientKey] = client
+	}
+	return client, cached.err
+}
+
+// errorRoundTripper fails all the requests with the error of the invalid transport settings
+type errorRoundTripper struct {
+	err error
+}
+
+// RoundTrip returns the error
+func (e errorRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, e.err
+}
+
+// closeIdleConnections closes the idle connections of the cached transports
+func (c *clientCache) closeIdleConnections() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, cached := range c.transports {
+		if tr, ok := cached.transport.(*http.Transport); ok {
+			tr.CloseIdleConnections()
+		}
+	}
+}
+
+func newTransport(key transportKey, tlsAuth TLSAuthenticator) (tr *http.Transport, err error) {
+	config := key.config
+	tr = &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       &tls.Config{InsecureSkipVerify: key.insecureSkipVerify},
+		ForceAttemptHTTP2:     !config.DisableHTTP2,
+		MaxIdleConns:          valueOrDefault(config.MaxIdleConns, defaultMaxIdleConns),
+		MaxIdleConnsPerHost:   valueOrDefault(config.MaxIdleConnsPerHost, defaultMaxIdleConnsPerHost),
+		MaxConnsPerHost:       config.MaxConnsPerHost,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	if tlsAuth != nil {
+		tlsAuth.ConfigureTLS(tr.TLSClientConfig)
+	}
+	if tr.IdleConnTimeout <= 0 {
+		tr.IdleConnTimeout = defaultIdleConnTimeout
+	}
+	if config.DisableHTTP2 {
+		// a non-nil empty map disables HTTP/2
+		tr.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	if key.caCertFile != "" {
+		if tr.TLSClientConfig.RootCAs, err = loadCACertFile(key.caCertFile); err != nil {
+			return
+		}
+	}
+	err = setProxy(key.proxy, key.proxyAuth, key.noProxy, key.proxyFromEnv, tr)
+	return
+}
+
+// loadCACertFile returns the system cert pool with the extra PEM encoded certificates
+func loadCACertFile(caCertFile string) (pool *x509.CertPool, err error) {
+	var data []byte
+	if data, err = os.ReadFile(caCertFile); err != nil {
+		err = fmt.Errorf("failed to read the CA certificates, error is %v", err)
+		return
+	}
+	if pool, err = x509.SystemCertPool(); err != nil || pool == nil {
+		pool, err = x509.NewCertPool(), nil
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		err = fmt.Errorf("no valid certificates found in %s", caCertFile)
+	}
+	return
+}
+
+// setProxy sets the proxy for a transport.
+// The proxy could be HTTP, HTTPS or SOCKS5 one, noProxy is a comma-separated list of hosts which skip the proxy,
+// the environment variables HTTP_PROXY, HTTPS_PROXY and NO_PROXY take effect if fromEnv is true and there is
+// no explicit setting.
+// An explicit proxy without noProxy and fromEnv applies to all the hosts, including localhost and the loopback addresses.
+func setProxy(proxy, proxyAuth, noProxy string, fromEnv bool, tr *http.Transport) (err error) {
+	config := &httpproxy.Config{}
+	if fromEnv {
+		config = httpproxy.FromEnvironment()
+	}
+	if noProxy != "" {
+		config.NoProxy = noProxy
+	}
+
+	if proxy != "" {
+		var proxyURL *url.URL
+		if proxyURL, err = parseProxyURL(proxy); err != nil {
+			return
+		}
+		switch proxyURL.Scheme {
+		case "socks5", "socks5h":
+			// there is no CONNECT request for the SOCKS5 proxy, the credentials go with the URL
+			if proxyAuth != "" && proxyURL.User == nil {
+				if name, password, ok := strings.Cut(proxyAuth, ":"); ok {
+					proxyURL.User = url.UserPassword(name, password)
+				} else {
+					proxyURL.User = url.User(proxyAuth)
+				}
+			}
+		}
+		config.HTTPProxy = proxyURL.String()
+		config.HTTPSProxy = config.HTTPProxy
+
+		if noProxy == "" && !fromEnv {
+			// the ProxyFunc of httpproxy always skips localhost, keep the semantics of http.ProxyURL instead
+			tr.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+
+	if tr.Proxy == nil {
+		if config.HTTPProxy == "" && config.HTTPSProxy == "" {
+			return
+		}
+		proxyFunc := config.ProxyFunc()
+		tr.Proxy = func(request *http.Request) (*url.URL, error) {
+			return proxyFunc(request.URL)
+		}
+	}
+	if proxyAuth != "" {
+		basicAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte(proxyAuth))
+		tr.ProxyConnectHeader = http.Header{}
+		tr.ProxyConnectHeader.Add("Proxy-Authorization", basicAuth)
+	}
+	return
+}
+
+// parseProxyURL parses and validates the proxy address
+func parseProxyURL(proxy string) (proxyURL *url.URL, err error) {
+	if proxyURL, err = url.Parse(proxy); err != nil {
+		err = fmt.Errorf("invalid proxy %q, error is %v", proxy, err)
+		return
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		err = fmt.Errorf("invalid proxy %q, the scheme must be one of http, https, socks5 and socks5h", proxy)
+		return
+	}
+	if proxyURL.Host == "" {
+		err = fmt.Errorf("invalid proxy %q, the host is empty", proxy)
+	}
+	return
+}
+
+func valueOrDefault(val, defaultVal int) int {
+	if val <= 0 {
+		return defaultVal
+	}
+	return val
+}
+
+// Transport returns the RoundTripper which sends the requests, it is the shared pooled transport if there
+// is no RoundTripper. It is useful for wrapping the transport, the settings changed after wrapping do not take effect.
+func (j *JenkinsCore) Transport() http.RoundTripper {
+	if j.RoundTripper != nil {
+		return j.RoundTripper
+	}
+	return j.GetClient().Transport
+}
+
+// CloseIdleConnections closes the idle connections of the shared HTTP client
+func (j *JenkinsCore) CloseIdleConnections() {
+	j.getClientCache().closeIdleConnections()
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("transport test", func() {
+	Context("proxy", func() {
+		It("invalid proxy does not crash the client", func() {
+			jenkinsCore := &JenkinsCore{URL: "http://localhost", Proxy: "htp//typo"}
+			Expect(jenkinsCore.Validate()).To(HaveOccurred())
+
+			client := jenkinsCore.GetClient()
+			Expect(client).NotTo(BeNil())
+			_, _, err := jenkinsCore.Request(http.MethodGet, "/fake", nil, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid proxy"))
+		})
+
+		It("recover once the proxy is fixed", func() {
+			jenkinsCore := &JenkinsCore{URL: "http://localhost", Proxy: "ftp://localhost"}
+			Expect(jenkinsCore.Validate()).To(HaveOccurred())
+
+			jenkinsCore.Proxy = "http://localhost:8080"
+			Expect(jenkinsCore.Validate()).To(Succeed())
+		})
+
+		It("proxy localhost without NoProxy", func() {
+			tr := &http.Transport{}
+			Expect(setProxy("http://proxy:8080", "", "", false, tr)).To(Succeed())
+
+			for _, target := range []string{"http://localhost:8080/api/json", "http://127.0.0.1/api/json", "http://jenkins.io/api/json"} {
+				request, _ := http.NewRequest(http.MethodGet, target, nil)
+				proxyURL, err := tr.Proxy(request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proxyURL.String()).To(Equal("http://proxy:8080"))
+			}
+		})
+
+		It("skip localhost with NoProxy", func() {
+			tr := &http.Transport{}
+			Expect(setProxy("http://proxy:8080", "", "jenkins.example.com", false, tr)).To(Succeed())
+
+			request, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/api/json", nil)
+			proxyURL, err := tr.Proxy(request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proxyURL).To(BeNil())
+		})
+
+		It("skip the proxy for the hosts in NoProxy", func() {
+			tr := &http.Transport{}
+			Expect(setProxy("http://proxy:8080", "", "jenkins.example.com,.internal", false, tr)).To(Succeed())
+
+			request, _ := http.NewRequest(http.MethodGet, "http://jenkins.example.com/api/json", nil)
+			proxyURL, err := tr.Proxy(request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proxyURL).To(BeNil())
+
+			request, _ = http.NewRequest(http.MethodGet, "http://ci.internal/api/json", nil)
+			proxyURL, err = tr.Proxy(request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proxyURL).To(BeNil())
+
+			request, _ = http.NewRequest(http.MethodGet, "http://jenkins.io/api/json", nil)
+			proxyURL, err = tr.Proxy(request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proxyURL.String()).To(Equal("http://proxy:8080"))
+		})
+
+		It("SOCKS5 proxy with credentials", func() {
+			tr := &http.Transport{}
+			Expect(setProxy("socks5://proxy:1080", "user:pass", "", false, tr)).To(Succeed())
+
+			request, _ := http.NewRequest(http.MethodGet, "https://jenkins.io/api/json", nil)
+			proxyURL, err := tr.Proxy(request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proxyURL.Scheme).To(Equal("socks5"))
+			Expect(proxyURL.User.String()).To(Equal("user:pass"))
+		})
+
+		It("take the proxy from the environment", func() {
+			GinkgoT().Setenv("HTTPS_PROXY", "http://env-proxy:3128")
+			GinkgoT().Setenv("NO_PROXY", "jenkins.example.com")
+			tr := &http.Transport{}
+			Expect(setProxy("", "", "", true, tr)).To(Succeed())
+
+			request, _ := http.NewRequest(http.MethodGet, "https://jenkins.io/api/json", nil)
+			proxyURL, err := tr.Proxy(request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proxyURL.String()).To(Equal("http://env-proxy:3128"))
+
+			request, _ = http.NewRequest(http.MethodGet, "https://jenkins.example.com/api/json", nil)
+			proxyURL, err = tr.Proxy(request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proxyURL).To(BeNil())
+		})
+	})
+
+	Context("New", func() {
+		It("with valid options", func() {
+			jenkinsCore, err := New(WithProxy("socks5://localhost:1080", ""), WithNoProxy("localhost"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jenkinsCore.Proxy).To(Equal("socks5://localhost:1080"))
+			Expect(jenkinsCore.NoProxy).To(Equal("localhost"))
+		})
+
+		It("with an invalid proxy", func() {
+			jenkinsCore, err := New(WithProxy("localhost:1080", ""))
+			Expect(err).To(HaveOccurred())
+			Expect(jenkinsCore).To(BeNil())
+		})
+
+		It("with an invalid CA bundle", func() {
+			caFile := filepath.Join(GinkgoT().TempDir(), "ca.pem")
+			Expect(os.WriteFile(caFile, []byte("fake"), 0600)).To(Succeed())
+
+			_, err := New(WithCACertFile(caFile))
+			Expect(err).To(HaveOccurred())
+
+			_, err = New(WithCACertFile(filepath.Join(GinkgoT().TempDir(), "none.pem")))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
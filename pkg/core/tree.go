@@ -0,0 +1,262 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Tree builds the value of the tree parameter of the Jenkins JSON API,
+// such as builds[number,result,timestamp]{0,50}
+// See also https://www.jenkins.io/doc/book/using/remote-access-api/
+type Tree struct {
+	fields []treeField
+}
+
+type treeField struct {
+	name     string
+	children *Tree
+	span     string
+}
+
+// NewTree creates a Tree with the leaf fields
+func NewTree(names ...string) *Tree {
+	t := &Tree{}
+	for _, name := range names {
+		t.Add(name, nil)
+	}
+	return t
+}
+
+// Add appends a field, the children can be nil for a leaf field
+func (t *Tree) Add(name string, children *Tree) *Tree {
+	t.fields = append(t.fields, treeField{name: name, children: children})
+	return t
+}
+
+// Range limits the items of a list field to the ones in [from, to).
+// A negative from or to means the range is open on that side.
+// Nothing happens if there is no such field.
+func (t *Tree) Range(name string, from, to int) *Tree {
+	for i := range t.fields {
+		if t.fields[i].name == name {
+			t.fields[i].span = formatRange(from, to)
+		}
+	}
+	return t
+}
+
+// Set replaces the children of a field, the children can be nil to make it a leaf field.
+// Nothing happens if there is no such field.
+func (t *Tree) Set(name string, children *Tree) *Tree {
+	for i := range t.fields {
+		if t.fields[i].name == name {
+			t.fields[i].children = children
+		}
+	}
+	return t
+}
+
+// String returns the tree expression
+func (t *Tree) String() string {
+	if t == nil {
+		return ""
+	}
+	var builder strings.Builder
+	t.write(&builder)
+	return builder.String()
+}
+
+func (t *Tree) write(builder *strings.Builder) {
+	for i, field := range t.fields {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteString(field.name)
+		if field.children != nil && len(field.children.fields) > 0 {
+			builder.WriteByte('[')
+			field.children.write(builder)
+			builder.WriteByte(']')
+		}
+		builder.WriteString(field.span)
+	}
+}
+
+func formatRange(from, to int) string {
+	var lower, upper string
+	if from >= 0 {
+		lower = strconv.Itoa(from)
+	}
+	if to >= 0 {
+		upper = strconv.Itoa(to)
+	}
+	return "{" + lower + "," + upper + "}"
+}
+
+var treeCache sync.Map
+
+// TreeOf generates the Tree from the fields of the type of v, it returns nil if v is not a struct.
+//
+// The field name is taken from the tree tag, then the json tag, then the lower camel case of the Go name.
+// Nested structs, pointers and slices of structs become sub trees, and embedded structs are flattened.
+// The tree tag accepts a name and an optional range, or "-" to skip the field:
+//
+//	Builds  []Build `tree:"{0,50}"`
+//	QueueID int     `tree:"queueId"`
+func TreeOf(v interface{}) *Tree {
+	return treeOfType(reflect.TypeOf(v))
+}
+
+// TreeFor is the same as TreeOf but accepts a type parameter
+func TreeFor[T any]() *Tree {
+	return treeOfType(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+func treeOfType(typ reflect.Type) *Tree {
+	if typ == nil {
+		return nil
+	}
+	if cached, ok := treeCache.Load(typ); ok {
+		return cached.(*Tree).clone()
+	}
+	t := buildTree(typ, map[reflect.Type]bool{})
+	treeCache.Store(typ, t)
+	return t.clone()
+}
+
+// clone copies the Tree, so the cached one can not be changed by the callers
+func (t *Tree) clone() *Tree {
+	if t == nil {
+		return nil
+	}
+	fields := make([]treeField, len(t.fields))
+	for i, field := range t.fields {
+		field.children = field.children.clone()
+		fields[i] = field
+	}
+	return &Tree{fields: fields}
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// structOf returns the struct type behind the pointers and slices, or nil if it should be a leaf field
+func structOf(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ.Implements(jsonUnmarshalerType) ||
+		reflect.PtrTo(typ).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+	return typ
+}
+
+func buildTree(typ reflect.Type, visiting map[reflect.Type]bool) *Tree {
+	if typ = structOf(typ); typ == nil {
+		return nil
+	}
+	// a recursive type can only be expanded once, the inner one stays as a leaf
+	visiting[typ] = true
+	defer delete(visiting, typ)
+
+	t := &Tree{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" && structOf(field.Type) != nil {
+			if embedded := buildTree(field.Type, visiting); embedded != nil {
+				t.fields = append(t.fields, embedded.fields...)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name, span, skip := parseTreeTag(field)
+		if skip {
+			continue
+		}
+
+		var children *Tree
+		if sub := structOf(field.Type); sub != nil && !visiting[sub] {
+			children = buildTree(sub, visiting)
+		}
+		t.fields = append(t.fields, treeField{name: name, children: children, span: span})
+	}
+	return t
+}
+
+func parseTreeTag(field reflect.StructField) (name, span string, skip bool) {
+	tag := field.Tag.Get("tree")
+	if tag == "-" {
+		return "", "", true
+	}
+	if index := strings.Index(tag, "{"); index >= 0 {
+		name, span = tag[:index], tag[index:]
+	} else {
+		name = tag
+	}
+	if name != "" {
+		return
+	}
+
+	jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+	switch jsonName {
+	case "-":
+		skip = true
+	case "":
+		name = lowerCamelCase(field.Name)
+	default:
+		name = jsonName
+	}
+	return
+}
+
+// lowerCamelCase converts the Go field name to the name in the Jenkins API, such as URL to url, NextBuild to nextBuild
+func lowerCamelCase(name string) string {
+	runes := []rune(name)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// APIOptions represents the query parameters of the Jenkins JSON API
+type APIOptions struct {
+	// Tree is generated from the result type if it is nil
+	Tree *Tree
+	// Depth is omitted if it is zero
+	Depth int
+	// FullTree fetches all the fields instead of the ones in the Tree
+	FullTree bool
+}
+
+// GetAPI sends a GET request to the JSON API of the path, then parses the response as T.
+// Only the fields of T are fetched unless there is a Tree or FullTree in the options.
+func GetAPI[T any](ctx context.Context, j *JenkinsCore, path string, opts APIOptions) (result T, err error) {
+	request := NewRequestWithContext(ctx, strings.TrimSuffix(path, "/")+"/api/json", j)
+	if !opts.FullTree {
+		tree := opts.Tree
+		if tree == nil {
+			tree = TreeFor[T]()
+		}
+		if expr := tree.String(); expr != "" {
+			request.AddQuery("tree", expr)
+		}
+	}
+	if opts.Depth != 0 {
+		request.AddQuery("depth", strconv.Itoa(opts.Depth))
+	}
+
+	if err = request.Do(); err == nil {
+		err = request.GetObject(&result)
+	}
+	return
+}
@@ -0,0 +1,146 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+type treeBuild struct {
+	Number    int
+	Result    string
+	Timestamp int64
+}
+
+type treeRef struct {
+	Number int
+	URL    string
+}
+
+type treeJob struct {
+	Class      string `json:"_class"`
+	Name       string
+	FullName   string      `json:"fullName,omitempty"`
+	Builds     []treeBuild `tree:"{0,50}"`
+	LastBuild  *treeRef
+	QueueID    int         `tree:"queueId"`
+	Actions    interface{} `tree:"-"`
+	Ignored    string      `json:"-"`
+	Parameters map[string]string
+	Jobs       []treeJob
+	internal   string
+}
+
+type treeEmbedded struct {
+	treeRef
+	Building bool
+}
+
+var _ = Describe("tree test", func() {
+	It("build a tree by hand", func() {
+		tree := NewTree("name").
+			Add("builds", NewTree("number", "result", "timestamp")).
+			Range("builds", 0, 50)
+		Expect(tree.String()).To(Equal("name,builds[number,result,timestamp]{0,50}"))
+
+		Expect(NewTree("builds").Range("builds", 10, -1).String()).To(Equal("builds{10,}"))
+		Expect(NewTree("builds").Range("builds", -1, 5).String()).To(Equal("builds{,5}"))
+		Expect((*Tree)(nil).String()).To(BeEmpty())
+	})
+
+	It("replace the children of a field", func() {
+		tree := TreeFor[treeJob]().Set("builds", NewTree("number")).Set("lastBuild", nil).Set("missing", NewTree("x"))
+		Expect(tree.String()).To(Equal("_class,name,fullName,builds[number]{0,50},lastBuild,queueId,parameters,jobs"))
+	})
+
+	It("generate a tree from the struct tags", func() {
+		Expect(TreeFor[treeJob]().String()).To(Equal("_class,name,fullName,builds[number,result,timestamp]{0,50}," +
+			"lastBuild[number,url],queueId,parameters,jobs"))
+		Expect(TreeOf(&treeEmbedded{}).String()).To(Equal("number,url,building"))
+		Expect(TreeFor[[]treeRef]().String()).To(Equal("number,url"))
+		Expect(TreeFor[string]()).To(BeNil())
+		Expect(TreeOf(nil)).To(BeNil())
+	})
+
+	It("the generated tree is a copy", func() {
+		TreeFor[treeRef]().Add("extra", nil)
+		Expect(TreeFor[treeRef]().String()).To(Equal("number,url"))
+	})
+
+	It("lower camel case", func() {
+		Expect(lowerCamelCase("URL")).To(Equal("url"))
+		Expect(lowerCamelCase("NextBuild")).To(Equal("nextBuild"))
+		Expect(lowerCamelCase("URLPath")).To(Equal("urlPath"))
+		Expect(lowerCamelCase("name")).To(Equal("name"))
+	})
+
+	Context("GetAPI", func() {
+		var (
+			ctrl         *gomock.Controller
+			roundTripper *mhttp.MockRoundTripper
+			jenkinsCore  JenkinsCore
+		)
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			roundTripper = mhttp.NewMockRoundTripper(ctrl)
+			jenkinsCore = JenkinsCore{URL: "http://localhost", RoundTripper: roundTripper}
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		prepare := func(rawURL, body string) *http.Response {
+			request, _ := http.NewRequest(http.MethodGet, rawURL, nil)
+			response := &http.Response{
+				StatusCode: http.StatusOK,
+				Request:    request,
+				Body:       io.NopCloser(bytes.NewBufferString(body)),
+			}
+			roundTripper.EXPECT().RoundTrip(NewRequestMatcher(request).WithQuery()).Return(response, nil)
+			return response
+		}
+
+		It("with the generated tree", func() {
+			prepare("http://localhost/job/a/api/json?tree=number%2Curl", `{"number":1,"url":"/job/a/1/"}`)
+
+			ref, err := GetAPI[*treeRef](context.Background(), &jenkinsCore, "/job/a/", APIOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ref).To(Equal(&treeRef{Number: 1, URL: "/job/a/1/"}))
+		})
+
+		It("with a custom tree and depth", func() {
+			prepare("http://localhost/job/a/api/json?depth=2&tree=number", `{"number":1}`)
+
+			ref, err := GetAPI[treeRef](context.Background(), &jenkinsCore, "/job/a", APIOptions{
+				Tree:  NewTree("number"),
+				Depth: 2,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ref.Number).To(Equal(1))
+		})
+
+		It("with the full tree", func() {
+			prepare("http://localhost/job/a/api/json", `{"number":1}`)
+
+			_, err := GetAPI[treeRef](context.Background(), &jenkinsCore, "/job/a", APIOptions{FullTree: true})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("with an error status code", func() {
+			response := prepare("http://localhost/job/a/api/json?tree=number%2Curl", `not found`)
+			response.StatusCode = http.StatusNotFound
+
+			_, err := GetAPI[treeRef](context.Background(), &jenkinsCore, "/job/a", APIOptions{})
+			Expect(err).To(HaveOccurred())
+			Expect(IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
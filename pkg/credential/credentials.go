@@ -1,6 +1,7 @@
 package credential
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -16,10 +17,20 @@ type CredentialsManager struct {
 	core.JenkinsCore
 }
 
+// NewCredentialsManager creates a CredentialsManager which shares the HTTP client with the JenkinsCore
+func NewCredentialsManager(jenkins *core.JenkinsCore) *CredentialsManager {
+	return &CredentialsManager{JenkinsCore: jenkins.Clone()}
+}
+
 // GetList returns the credential list
 func (c *CredentialsManager) GetList(store string) (credentialList List, err error) {
-	api := fmt.Sprintf("/credentials/store/%s/domain/_/api/json?pretty=true&depth=1", store)
-	request := core.NewRequest(api, &c.JenkinsCore)
+	return c.GetListContext(context.Background(), store)
+}
+
+// GetListContext is the same as GetList but accepts a context
+func (c *CredentialsManager) GetListContext(ctx context.Context, store string) (credentialList List, err error) {
+	api := storeAPI(store) + "/api/json?pretty=true&depth=1"
+	request := core.NewRequestWithContext(ctx, api, &c.JenkinsCore)
 	if err = request.Do(); err == nil {
 		err = request.GetObject(&credentialList)
 	}
@@ -28,65 +39,99 @@ func (c *CredentialsManager) GetList(store string) (credentialList List, err err
 
 // Delete deletes a credential by id from a store
 func (c *CredentialsManager) Delete(store, id string) (err error) {
-	api := fmt.Sprintf("/credentials/store/%s/domain/_/credential/%s/doDelete", store, id)
-	request := core.NewRequest(api, &c.JenkinsCore)
+	return c.DeleteContext(context.Background(), store, id)
+}
+
+// DeleteContext is the same as Delete but accepts a context
+func (c *CredentialsManager) DeleteContext(ctx context.Context, store, id string) (err error) {
+	api := storeAPI(store) + "/credential/" + core.EscapePathSegment(id) + "/doDelete"
+	request := core.NewRequestWithContext(ctx, api, &c.JenkinsCore)
 	err = request.WithPostMethod().Do()
 	return
 }
 
-// DeleteInFolder deletes a credential by id from a folder
+// DeleteInFolder deletes a credential by id from a folder.
+// The folder is the full name separated by slashes, e.g. parent/folder, the legacy form parent/job/folder works as well
 func (c *CredentialsManager) DeleteInFolder(folder, id string) (err error) {
-	api := fmt.Sprintf("/job/%s/credentials/store/folder/domain/_/credential/%s/doDelete", folder, id)
-	request := core.NewRequest(api, &c.JenkinsCore)
+	return c.DeleteInFolderContext(context.Background(), folder, id)
+}
+
+// DeleteInFolderContext is the same as DeleteInFolder but accepts a context
+func (c *CredentialsManager) DeleteInFolderContext(ctx context.Context, folder, id string) (err error) {
+	api := folderStoreAPI(folder) + "/credential/" + core.EscapePathSegment(id) + "/doDelete"
+	request := core.NewRequestWithContext(ctx, api, &c.JenkinsCore)
 	err = request.WithPostMethod().Do()
 	return
 }
 
 // Create create a credential in Jenkins
 func (c *CredentialsManager) Create(store, credential string) (err error) {
-	api := fmt.Sprintf("/credentials/store/%s/domain/_/createCredentials", store)
+	return c.CreateContext(context.Background(), store, credential)
+}
+
+// CreateContext is the same as Create but accepts a context
+func (c *CredentialsManager) CreateContext(ctx context.Context, store, credential string) (err error) {
+	api := storeAPI(store) + "/createCredentials"
 	core.Logger.Debug("create credential", slog.String("api", api), slog.String("payload", credential))
 
 	formData := url.Values{}
 	formData.Add("json", fmt.Sprintf(`{"credentials": %s}`, credential))
 
-	request := core.NewRequest(api, &c.JenkinsCore)
+	request := core.NewRequestWithContext(ctx, api, &c.JenkinsCore)
 	request.AsPostFormRequest().WithValues(formData)
 	err = request.Do()
 	return
 }
 
-// CreateInFolder creates a credential in a folder
+// CreateInFolder creates a credential in a folder.
+// The folder is the full name separated by slashes, e.g. parent/folder, the legacy form parent/job/folder works as well
 func (c *CredentialsManager) CreateInFolder(folder string, cre interface{}) (err error) {
-	api := fmt.Sprintf("/job/%s/credentials/store/folder/domain/_/createCredentials", folder)
+	return c.CreateInFolderContext(context.Background(), folder, cre)
+}
+
+// CreateInFolderContext is the same as CreateInFolder but accepts a context
+func (c *CredentialsManager) CreateInFolderContext(ctx context.Context, folder string, cre interface{}) (err error) {
+	api := folderStoreAPI(folder) + "/createCredentials"
 
 	formData := url.Values{}
 	formData.Add("json", fmt.Sprintf(`{"credentials": %s}`, util.TOJSON(cre)))
 
-	request := core.NewRequest(api, &c.JenkinsCore)
+	request := core.NewRequestWithContext(ctx, api, &c.JenkinsCore)
 	request.AsPostFormRequest().WithValues(formData)
 	err = request.Do()
 	return
 }
 
-// UpdateInFolder updates a credential in a folder
+// UpdateInFolder updates a credential in a folder.
+// The folder is the full name separated by slashes, e.g. parent/folder, the legacy form parent/job/folder works as well
 func (c *CredentialsManager) UpdateInFolder(folder, id string, cre interface{}) (err error) {
-	api := fmt.Sprintf("/job/%s/credentials/store/folder/domain/_/credential/%s/updateSubmit", folder, id)
+	return c.UpdateInFolderContext(context.Background(), folder, id, cre)
+}
+
+// UpdateInFolderContext is the same as UpdateInFolder but accepts a context
+func (c *CredentialsManager) UpdateInFolderContext(ctx context.Context, folder, id string, cre interface{}) (err error) {
+	api := folderStoreAPI(folder) + "/credential/" + core.EscapePathSegment(id) + "/updateSubmit"
 
 	formData := url.Values{}
 	formData.Add("json", util.TOJSON(cre))
 
-	request := core.NewRequest(api, &c.JenkinsCore)
+	request := core.NewRequestWithContext(ctx, api, &c.JenkinsCore)
 	request.AsPostFormRequest().WithValues(formData).AcceptStatusCode(http.StatusNotFound)
 	err = request.Do()
 	return
 }
 
-// GetInFolder gets a credential in a folder
+// GetInFolder gets a credential in a folder.
+// The folder is the full name separated by slashes, e.g. parent/folder, the legacy form parent/job/folder works as well
 func (c *CredentialsManager) GetInFolder(folder, id string) (cre Credential, err error) {
-	api := fmt.Sprintf("/job/%s/credentials/store/folder/domain/_/credential/%s", folder, id)
+	return c.GetInFolderContext(context.Background(), folder, id)
+}
 
-	request := core.NewRequest(api, &c.JenkinsCore)
+// GetInFolderContext is the same as GetInFolder but accepts a context
+func (c *CredentialsManager) GetInFolderContext(ctx context.Context, folder, id string) (cre Credential, err error) {
+	api := folderStoreAPI(folder) + "/credential/" + core.EscapePathSegment(id)
+
+	request := core.NewRequestWithContext(ctx, api, &c.JenkinsCore)
 	if err = request.WithValues(url.Values{"depth": {"2"}}).Do(); err == nil {
 		err = request.GetObject(&cre)
 	}
@@ -95,20 +140,30 @@ func (c *CredentialsManager) GetInFolder(folder, id string) (cre Credential, err
 
 // CreateUsernamePassword create username and password credential in Jenkins
 func (c *CredentialsManager) CreateUsernamePassword(store string, cred UsernamePasswordCredential) (err error) {
+	return c.CreateUsernamePasswordContext(context.Background(), store, cred)
+}
+
+// CreateUsernamePasswordContext is the same as CreateUsernamePassword but accepts a context
+func (c *CredentialsManager) CreateUsernamePasswordContext(ctx context.Context, store string, cred UsernamePasswordCredential) (err error) {
 	var payload []byte
 	cred.Class = "com.cloudbees.plugins.credentials.impl.UsernamePasswordCredentialsImpl"
 	if payload, err = json.Marshal(cred); err == nil {
-		err = c.Create(store, string(payload))
+		err = c.CreateContext(ctx, store, string(payload))
 	}
 	return
 }
 
 // CreateSecret create token credential in Jenkins
 func (c *CredentialsManager) CreateSecret(store string, cred StringCredentials) (err error) {
+	return c.CreateSecretContext(context.Background(), store, cred)
+}
+
+// CreateSecretContext is the same as CreateSecret but accepts a context
+func (c *CredentialsManager) CreateSecretContext(ctx context.Context, store string, cred StringCredentials) (err error) {
 	var payload []byte
 	cred.Class = "org.jenkinsci.plugins.plaincredentials.impl.StringCredentialsImpl"
 	if payload, err = json.Marshal(cred); err == nil {
-		err = c.Create(store, string(payload))
+		err = c.CreateContext(ctx, store, string(payload))
 	}
 	return
 }
@@ -192,3 +247,34 @@ const (
 	// GLOBALScope is the Jenkins class
 	GLOBALScope = "GLOBAL"
 )
+
+// storeAPI returns the API of the global domain in a credential store
+func storeAPI(store string) string {
+	return "/credentials/store/" + core.EscapePathSegment(store) + "/domain/_"
+}
+
+// folderStoreAPI returns the API of the global domain in the credential store of a folder.
+// The folder is the full name, e.g. parent/folder. The legacy form parent/job/folder is accepted as well,
+// so a full name whose every other name is job, e.g. a/job/b, is taken as the legacy form.
+func folderStoreAPI(folder string) string {
+	return parseFolder(folder).String() + "/credentials/store/folder/domain/_"
+}
+
+// parseFolder parses the full name of a folder, or the legacy form which separates the names by /job/
+func parseFolder(folder string) core.ItemPath {
+	names := core.ParseFullName(folder)
+	if len(names) < 3 || len(names)%2 == 0 {
+		return names
+	}
+	for i := 1; i < len(names); i += 2 {
+		if names[i] != "job" {
+			return names
+		}
+	}
+
+	itemPath := core.NewItemPath()
+	for i := 0; i < len(names); i += 2 {
+		itemPath = itemPath.Job(names[i])
+	}
+	return itemPath
+}
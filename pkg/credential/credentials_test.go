@@ -223,6 +223,32 @@ func TestGetInFolder(t *testing.T) {
 				"", "", "fake", "id", payload)
 		},
 		wantErr: false,
+	}, {
+		name:   "get credential in a nested folder by the full name",
+		folder: "parent/fake",
+		id:     "id",
+		cre:    &credential.Credential{ID: "id"},
+		prepare: func(t *testing.T, obj interface{}, manager *credential.CredentialsManager) {
+			roundTripper := mhttp.NewMockRoundTripper(gomock.NewController(t))
+			manager.URL = "http://localhost"
+			manager.RoundTripper = roundTripper
+			credential.PrepareForGetCredentialInFolder(roundTripper, manager.URL,
+				"", "", "parent/job/fake", "id", nil)
+		},
+		wantErr: false,
+	}, {
+		name:   "get credential in a nested folder by the legacy path",
+		folder: "parent/job/fake",
+		id:     "id",
+		cre:    &credential.Credential{ID: "id"},
+		prepare: func(t *testing.T, obj interface{}, manager *credential.CredentialsManager) {
+			roundTripper := mhttp.NewMockRoundTripper(gomock.NewController(t))
+			manager.URL = "http://localhost"
+			manager.RoundTripper = roundTripper
+			credential.PrepareForGetCredentialInFolder(roundTripper, manager.URL,
+				"", "", "parent/job/fake", "id", nil)
+		},
+		wantErr: false,
 	}}
 	for i, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,84 @@
+package instrumentation
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// variableSegments maps the collection segments of the Jenkins API to the placeholders of the following segments.
+// The paths are composed like job.ParseJobPath and the BlueOcean API helpers do,
+// e.g. /job/a/job/b/1/api/json or /blue/rest/organizations/jenkins/pipelines/a/branches/master/runs/1/
+var variableSegments = map[string]string{
+	"job":           "{name}",
+	"view":          "{view}",
+	"computer":      "{computer}",
+	"user":          "{user}",
+	"users":         "{user}",
+	"item":          "{id}",
+	"organizations": "{organization}",
+	"pipelines":     "{pipeline}",
+	"branches":      "{branch}",
+	"runs":          "{run}",
+	"nodes":         "{node}",
+	"steps":         "{step}",
+	"store":         "{store}",
+	"domain":        "{domain}",
+	"credential":    "{credential}",
+	"input":         "{input}",
+	"artifact":      "{artifact}",
+}
+
+// greedySegments are followed by a path which might contain any number of segments
+var greedySegments = map[string]bool{
+	"artifact": true,
+}
+
+// RouteTemplate returns the route template of a Jenkins API path, the variable segments are replaced by the
+// placeholders to keep the cardinality bounded. The folders are collapsed, both /job/a/1/api/json and
+// /job/a/job/b/1/api/json become /job/{name}/{id}/api/json. The query is removed.
+func RouteTemplate(path string) string {
+	if u, err := url.Parse(path); err == nil {
+		path = u.EscapedPath()
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	route := make([]string, 0, len(segments))
+	for i := 0; i < len(segments); i++ {
+		segment := segments[i]
+		switch {
+		case segment == "":
+			continue
+		case isNumber(segment):
+			route = append(route, "{id}")
+			continue
+		}
+
+		route = append(route, segment)
+		placeholder, ok := variableSegments[segment]
+		if !ok || i+1 >= len(segments) || segments[i+1] == "api" {
+			continue
+		}
+		route = append(route, placeholder)
+		i++
+		if greedySegments[segment] {
+			break
+		}
+
+		// collapse the nested folders
+		for i+2 < len(segments) && segments[i+1] == segment {
+			i += 2
+		}
+	}
+
+	template := "/" + strings.Join(route, "/")
+	if strings.HasSuffix(path, "/") && template != "/" {
+		template += "/"
+	}
+	return template
+}
+
+func isNumber(segment string) bool {
+	_, err := strconv.Atoi(segment)
+	return err == nil
+}
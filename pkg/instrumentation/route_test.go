@@ -0,0 +1,36 @@
+package instrumentation
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("route template test", func() {
+	DescribeTable("RouteTemplate",
+		func(path, expected string) {
+			Expect(RouteTemplate(path)).To(Equal(expected))
+		},
+		Entry("job", "/job/fake/api/json", "/job/{name}/api/json"),
+		Entry("build", "/job/fake/12/api/json", "/job/{name}/{id}/api/json"),
+		Entry("nested folders", "/job/a/job/b/job/c/lastBuild/api/json", "/job/{name}/lastBuild/api/json"),
+		Entry("with query", "/job/fake/1/logText/progressiveText?start=0", "/job/{name}/{id}/logText/progressiveText"),
+		Entry("list", "/computer/api/json", "/computer/api/json"),
+		Entry("queue item", "/queue/item/3/api/json", "/queue/item/{id}/api/json"),
+		Entry("artifact", "/job/fake/1/artifact/a/b/c.zip", "/job/{name}/{id}/artifact/{artifact}"),
+		Entry("credential", "/credentials/store/system/domain/_/credential/fake/config.xml",
+			"/credentials/store/{store}/domain/{domain}/credential/{credential}/config.xml"),
+		Entry("BlueOcean run", "/blue/rest/organizations/jenkins/pipelines/a/pipelines/b/branches/master/runs/1/",
+			"/blue/rest/organizations/{organization}/pipelines/{pipeline}/branches/{branch}/runs/{run}/"),
+		Entry("BlueOcean steps", "/blue/rest/organizations/jenkins/pipelines/a/runs/1/nodes/6/steps/",
+			"/blue/rest/organizations/{organization}/pipelines/{pipeline}/runs/{run}/nodes/{node}/steps/"),
+		Entry("BlueOcean current user", "/blue/rest/organizations/jenkins/user/",
+			"/blue/rest/organizations/{organization}/user/"),
+		Entry("BlueOcean users", "/blue/rest/organizations/jenkins/users/", "/blue/rest/organizations/{organization}/users/"),
+		Entry("BlueOcean user", "/blue/rest/organizations/jenkins/users/alice/",
+			"/blue/rest/organizations/{organization}/users/{user}/"),
+		Entry("BlueOcean favorites", "/blue/rest/users/alice/favorites/?start=0&limit=100", "/blue/rest/users/{user}/favorites/"),
+		Entry("BlueOcean favorite", "/blue/rest/organizations/jenkins/pipelines/a/branches/feature%252Fa/favorite",
+			"/blue/rest/organizations/{organization}/pipelines/{pipeline}/branches/{branch}/favorite"),
+		Entry("root", "/", "/"),
+	)
+})
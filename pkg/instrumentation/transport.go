@@ -0,0 +1,178 @@
+package instrumentation
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/verystar/jenkins-client/pkg/core"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/verystar/jenkins-client/pkg/instrumentation"
+
+// Attribute keys of the spans and the metrics
+const (
+	ControllerKey = attribute.Key("jenkins.controller")
+	RouteKey      = attribute.Key("http.route")
+	MethodKey     = attribute.Key("http.request.method")
+	StatusKey     = attribute.Key("http.response.status_code")
+)
+
+// Option is the setting of the instrumentation
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the TracerProvider, the global one is used by default
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the MeterProvider, the global one is used by default.
+// The metrics could be exported to Prometheus via the OpenTelemetry Prometheus exporter.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator which injects the trace context into the requests,
+// the global one is used by default
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// Transport creates a span and records the metrics for each Jenkins API call
+type Transport struct {
+	base       http.RoundTripper
+	controller string
+	basePath   string
+	userAgent  string
+
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	requests   metric.Int64Counter
+	errors     metric.Int64Counter
+	duration   metric.Float64Histogram
+}
+
+// NewTransport wraps the RoundTripper, jenkinsURL is the URL of the Jenkins controller
+func NewTransport(base http.RoundTripper, jenkinsURL string, opts ...Option) (transport *Transport, err error) {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport = &Transport{
+		base:       base,
+		controller: strings.TrimSuffix(jenkinsURL, "/"),
+		tracer:     c.tracerProvider.Tracer(instrumentationName),
+		propagator: c.propagator,
+	}
+	if u, parseErr := url.Parse(jenkinsURL); parseErr == nil {
+		transport.basePath = strings.TrimSuffix(u.Path, "/")
+	}
+
+	meter := c.meterProvider.Meter(instrumentationName)
+	if transport.requests, err = meter.Int64Counter("jenkins.client.requests",
+		metric.WithDescription("The number of the Jenkins API calls")); err != nil {
+		return nil, err
+	}
+	if transport.errors, err = meter.Int64Counter("jenkins.client.errors",
+		metric.WithDescription("The number of the failed Jenkins API calls, including the 5xx responses")); err != nil {
+		return nil, err
+	}
+	if transport.duration, err = meter.Float64Histogram("jenkins.client.duration",
+		metric.WithDescription("The latency of the Jenkins API calls"), metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Instrument wraps the transport of the JenkinsCore, the copies of it made before are not instrumented.
+// It returns the error of the invalid proxy or TLS settings.
+func Instrument(jenkins *core.JenkinsCore, opts ...Option) (err error) {
+	if err = jenkins.Validate(); err != nil {
+		return
+	}
+
+	var transport *Transport
+	if transport, err = NewTransport(jenkins.Transport(), jenkins.URL, opts...); err == nil {
+		if jenkins.UserAgent == "" && jenkins.RoundTripper == nil {
+			// JenkinsCore does not add the default User-Agent once there is a RoundTripper
+			transport.userAgent = core.DefaultUserAgent
+		}
+		jenkins.RoundTripper = transport
+	}
+	return
+}
+
+// RoundTrip sends the request in a span
+func (t *Transport) RoundTrip(req *http.Request) (response *http.Response, err error) {
+	route := RouteTemplate(strings.TrimPrefix(req.URL.EscapedPath(), t.basePath))
+	attrs := []attribute.KeyValue{
+		ControllerKey.String(t.controller),
+		RouteKey.String(route),
+		MethodKey.String(req.Method),
+	}
+
+	ctx, span := t.tracer.Start(req.Context(), req.Method+" "+route,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	defer span.End()
+
+	// the request must not be modified by a RoundTripper
+	req = req.Clone(ctx)
+	if t.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	start := time.Now()
+	response, err = t.base.RoundTrip(req)
+	elapsed := time.Since(start).Seconds()
+
+	failed := err != nil
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		status := StatusKey.Int(response.StatusCode)
+		span.SetAttributes(status)
+		attrs = append(attrs, status)
+		if response.StatusCode >= http.StatusInternalServerError {
+			failed = true
+			span.SetStatus(codes.Error, strconv.Itoa(response.StatusCode))
+		}
+	}
+
+	set := metric.WithAttributes(attrs...)
+	t.requests.Add(ctx, 1, set)
+	t.duration.Record(ctx, elapsed, set)
+	if failed {
+		t.errors.Add(ctx, 1, set)
+	}
+	return
+}
@@ -0,0 +1,136 @@
+package instrumentation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/core"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("transport test", func() {
+	var (
+		ctrl         *gomock.Controller
+		roundTripper *mhttp.MockRoundTripper
+		spans        *tracetest.SpanRecorder
+		reader       *sdkmetric.ManualReader
+		jenkinsCore  *core.JenkinsCore
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		spans = tracetest.NewSpanRecorder()
+		reader = sdkmetric.NewManualReader()
+
+		var err error
+		jenkinsCore, err = core.NewJenkinsCore("http://localhost/jenkins", core.WithRoundTripper(roundTripper))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(Instrument(jenkinsCore,
+			WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+			WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+			WithPropagator(propagation.TraceContext{}))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	collect := func() map[string]metricdata.Metrics {
+		data := metricdata.ResourceMetrics{}
+		Expect(reader.Collect(context.Background(), &data)).To(Succeed())
+		result := map[string]metricdata.Metrics{}
+		for _, scope := range data.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				result[m.Name] = m
+			}
+		}
+		return result
+	}
+
+	It("create a span and record the metrics", func() {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			Expect(req.Header.Get("traceparent")).NotTo(BeEmpty())
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Request:    req,
+				Body:       ioutil.NopCloser(bytes.NewBufferString("{}")),
+			}, nil
+		})
+
+		_, _, err := jenkinsCore.Request(http.MethodGet, "/job/a/job/b/3/api/json", nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		ended := spans.Ended()
+		Expect(ended).To(HaveLen(1))
+		Expect(ended[0].Name()).To(Equal("GET /job/{name}/{id}/api/json"))
+		attrs := map[string]string{}
+		for _, attr := range ended[0].Attributes() {
+			attrs[string(attr.Key)] = attr.Value.Emit()
+		}
+		Expect(attrs).To(HaveKeyWithValue(string(ControllerKey), "http://localhost/jenkins"))
+		Expect(attrs).To(HaveKeyWithValue(string(StatusKey), "200"))
+
+		metrics := collect()
+		Expect(metrics).To(HaveKey("jenkins.client.requests"))
+		Expect(metrics).To(HaveKey("jenkins.client.duration"))
+		Expect(metrics).NotTo(HaveKey("jenkins.client.errors"))
+		requests := metrics["jenkins.client.requests"].Data.(metricdata.Sum[int64])
+		Expect(requests.DataPoints).To(HaveLen(1))
+		Expect(requests.DataPoints[0].Value).To(Equal(int64(1)))
+	})
+
+	It("fail with the invalid transport settings", func() {
+		invalid, err := core.NewJenkinsCore("http://localhost/jenkins")
+		Expect(err).NotTo(HaveOccurred())
+		invalid.Proxy = "ftp://localhost"
+
+		Expect(Instrument(invalid)).To(MatchError(ContainSubstring("invalid proxy")))
+		Expect(invalid.RoundTripper).To(BeNil())
+	})
+
+	It("add the default User-Agent without changing the JenkinsCore", func() {
+		pooled, err := core.NewJenkinsCore("http://localhost/jenkins")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(Instrument(pooled)).To(Succeed())
+		Expect(pooled.UserAgent).To(BeEmpty())
+
+		transport := pooled.RoundTripper.(*Transport)
+		transport.base = roundTripper
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			Expect(req.Header.Get("User-Agent")).To(Equal(core.DefaultUserAgent))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Request:    req,
+				Body:       ioutil.NopCloser(bytes.NewBufferString("{}")),
+			}, nil
+		})
+
+		_, _, err = pooled.Request(http.MethodGet, "/api/json", nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("record the errors", func() {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(nil, errors.New("fake"))
+
+		_, _, err := jenkinsCore.Request(http.MethodGet, "/api/json", nil, nil)
+		Expect(err).To(HaveOccurred())
+
+		ended := spans.Ended()
+		Expect(ended).To(HaveLen(1))
+		Expect(ended[0].Status().Code).To(Equal(codes.Error))
+		Expect(collect()).To(HaveKey("jenkins.client.errors"))
+	})
+})
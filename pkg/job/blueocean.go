@@ -1,6 +1,7 @@
 package job
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -18,11 +19,18 @@ const (
 )
 
 // BlueOceanClient is client for operating pipelines via BlueOcean RESTful API.
+// The Branch of the options is the git branch name, such as feature/foo. The branch names in the responses
+// are the item names, such as feature%2Ffoo, convert them by core.DecodeBranchName before passing them back.
 type BlueOceanClient struct {
 	core.JenkinsCore
 	Organization string
 }
 
+// NewBlueOceanClient creates a BlueOceanClient which shares the HTTP client with the JenkinsCore
+func NewBlueOceanClient(jenkins *core.JenkinsCore, organization string) *BlueOceanClient {
+	return &BlueOceanClient{JenkinsCore: jenkins.Clone(), Organization: organization}
+}
+
 // Parameter contains name and value of an option.
 type Parameter struct {
 	Name  string `json:"name"`
@@ -31,45 +39,66 @@ type Parameter struct {
 
 // GetPipelines returns the Pipeline list which comes from the possible nest folders
 func (c *BlueOceanClient) GetPipelines(folders ...string) (pipelines []Pipeline, err error) {
+	return c.GetPipelinesContext(context.Background(), folders...)
+}
+
+// GetPipelinesContext is the same as GetPipelines but accepts a context
+func (c *BlueOceanClient) GetPipelinesContext(ctx context.Context, folders ...string) (pipelines []Pipeline, err error) {
 	api := c.getPipelineAPI(folders...)
-	err = c.RequestWithData(http.MethodGet, api,
+	err = c.RequestWithDataContext(ctx, http.MethodGet, api,
 		nil, nil, 200, &pipelines)
 	return
 }
 
 func (c *BlueOceanClient) getPipelineAPI(folders ...string) (api string) {
-	api = fmt.Sprintf("%s/%s/pipelines", organizationAPIPrefix, c.Organization)
+	api = fmt.Sprintf("%s/%s/pipelines", organizationAPIPrefix, core.EscapePathSegment(c.Organization))
 	for _, folder := range folders {
-		api = fmt.Sprintf("%s/%s/pipelines/", api, folder)
+		api = fmt.Sprintf("%s/%s/pipelines/", api, core.EscapePathSegment(folder))
 	}
 	return
 }
 
 // GetPipeline obtains Pipeline metadata with Pipeline name and folders.
 func (c *BlueOceanClient) GetPipeline(pipelineName string, folders ...string) (*Pipeline, error) {
+	return c.GetPipelineContext(context.Background(), pipelineName, folders...)
+}
+
+// GetPipelineContext is the same as GetPipeline but accepts a context
+func (c *BlueOceanClient) GetPipelineContext(ctx context.Context, pipelineName string, folders ...string) (*Pipeline, error) {
 	api := c.getGetPipelineAPI(pipelineName, folders...)
 	pipeline := &Pipeline{}
-	if err := c.RequestWithData(http.MethodGet, api, nil, nil, 200, pipeline); err != nil {
+	if err := c.RequestWithDataContext(ctx, http.MethodGet, api, nil, nil, 200, pipeline); err != nil {
 		return nil, err
 	}
 	return pipeline, nil
 }
 
 func (c *BlueOceanClient) getGetPipelineAPI(pipelineName string, folders ...string) string {
-	api := fmt.Sprintf("%s/%s", organizationAPIPrefix, c.Organization)
+	api := fmt.Sprintf("%s/%s", organizationAPIPrefix, core.EscapePathSegment(c.Organization))
 	folders = append(folders, pipelineName)
 	for _, folder := range folders {
-		api = fmt.Sprintf("%s/pipelines/%s", api, folder)
+		api = fmt.Sprintf("%s/pipelines/%s", api, core.EscapePathSegment(folder))
 	}
 	return api
 }
 
 // Search searches jobs via the BlueOcean API
 func (c *BlueOceanClient) Search(name string, start, limit int) (items []JenkinsItem, err error) {
-	api := fmt.Sprintf("%s/?q=pipeline:*%s*;type:pipeline;organization:%s;excludedFromFlattening=jenkins.branch.MultiBranchProject,com.cloudbees.hudson.plugins.folder.AbstractFolder&filter=no-folders&start=%d&limit=%d",
-		searchAPIPrefix, name, c.Organization, start, limit)
-	err = c.RequestWithData(http.MethodGet, api,
-		nil, nil, 200, &items)
+	return c.SearchContext(context.Background(), name, start, limit)
+}
+
+// SearchContext is the same as Search but accepts a context
+func (c *BlueOceanClient) SearchContext(ctx context.Context, name string, start, limit int) (items []JenkinsItem, err error) {
+	request := core.NewRequestWithContext(ctx, searchAPIPrefix+"/", &c.JenkinsCore).WithQuery(url.Values{
+		"q": {fmt.Sprintf("pipeline:*%s*;type:pipeline;organization:%s;excludedFromFlattening=%s",
+			name, c.Organization, "jenkins.branch.MultiBranchProject,com.cloudbees.hudson.plugins.folder.AbstractFolder")},
+		"filter": {"no-folders"},
+		"start":  {strconv.Itoa(start)},
+		"limit":  {strconv.Itoa(limit)},
+	})
+	if err = request.Do(); err == nil {
+		err = request.GetObject(&items)
+	}
 	return
 }
 
@@ -82,6 +111,11 @@ type BuildOption struct {
 
 // Build builds a pipeline for specific organization and pipelines.
 func (c *BlueOceanClient) Build(option BuildOption) (*PipelineRun, error) {
+	return c.BuildContext(context.Background(), option)
+}
+
+// BuildContext is the same as Build but accepts a context
+func (c *BlueOceanClient) BuildContext(ctx context.Context, option BuildOption) (*PipelineRun, error) {
 	var pr PipelineRun
 	var payloadReader io.Reader
 	// we allow developers to pass an empty parameters, but nil parameters
@@ -92,7 +126,7 @@ func (c *BlueOceanClient) Build(option BuildOption) (*PipelineRun, error) {
 		})
 		payloadReader = strings.NewReader(string(payloadBytes))
 	}
-	err := c.RequestWithData(http.MethodPost, c.getBuildAPI(option), getHeaders(), payloadReader, 200, &pr)
+	err := c.RequestWithDataContext(ctx, http.MethodPost, c.getBuildAPI(option), getHeaders(), payloadReader, 200, &pr)
 	if err != nil {
 		return nil, err
 	}
@@ -101,9 +135,9 @@ func (c *BlueOceanClient) Build(option BuildOption) (*PipelineRun, error) {
 
 func (c *BlueOceanClient) getBuildAPI(option BuildOption) string {
 	// validate option
-	api := fmt.Sprintf("%s/%s/%s", organizationAPIPrefix, c.Organization, parsePipelinePath(option.Pipelines))
+	api := fmt.Sprintf("%s/%s/%s", organizationAPIPrefix, core.EscapePathSegment(c.Organization), parsePipelinePath(option.Pipelines))
 	if option.Branch != "" {
-		api = fmt.Sprintf("%s/branches/%s", api, url.PathEscape(option.Branch))
+		api = fmt.Sprintf("%s/branches/%s", api, escapeBranch(option.Branch))
 	}
 	api = fmt.Sprintf("%s/runs/", api)
 	return api
@@ -118,8 +152,13 @@ type GetBuildOption struct {
 
 // GetBuild gets build result for specific organization, run ID and pipelines.
 func (c *BlueOceanClient) GetBuild(option GetBuildOption) (*PipelineRun, error) {
+	return c.GetBuildContext(context.Background(), option)
+}
+
+// GetBuildContext is the same as GetBuild but accepts a context
+func (c *BlueOceanClient) GetBuildContext(ctx context.Context, option GetBuildOption) (*PipelineRun, error) {
 	var pr PipelineRun
-	err := c.RequestWithData(http.MethodGet, c.getGetBuildAPI(option), getHeaders(), nil, 200, &pr)
+	err := c.RequestWithDataContext(ctx, http.MethodGet, c.getGetBuildAPI(option), getHeaders(), nil, 200, &pr)
 	if err != nil {
 		return nil, err
 	}
@@ -128,9 +167,14 @@ func (c *BlueOceanClient) GetBuild(option GetBuildOption) (*PipelineRun, error)
 
 // GetPipelineRuns returns a PipelineRun which in the possible nest folders
 func (c *BlueOceanClient) GetPipelineRuns(pipeline string, folders ...string) (runs []PipelineRun, err error) {
+	return c.GetPipelineRunsContext(context.Background(), pipeline, folders...)
+}
+
+// GetPipelineRunsContext is the same as GetPipelineRuns but accepts a context
+func (c *BlueOceanClient) GetPipelineRunsContext(ctx context.Context, pipeline string, folders ...string) (runs []PipelineRun, err error) {
 	api := c.getPipelineAPI(folders...)
-	api = fmt.Sprintf("%s/%s/runs/", api, pipeline)
-	err = c.RequestWithData(http.MethodGet, api,
+	api = fmt.Sprintf("%s/%s/runs/", api, core.EscapePathSegment(pipeline))
+	err = c.RequestWithDataContext(ctx, http.MethodGet, api,
 		nil, nil, 200, &runs)
 	return
 }
@@ -140,7 +184,7 @@ func (c *BlueOceanClient) getGetBuildAPI(option GetBuildOption) string {
 		Pipelines: option.Pipelines,
 		Branch:    option.Branch,
 	})
-	api = api + option.RunID + "/"
+	api = api + core.EscapePathSegment(option.RunID) + "/"
 	return api
 }
 
@@ -154,8 +198,13 @@ type GetNodesOption struct {
 
 // GetNodes gets nodes details
 func (c *BlueOceanClient) GetNodes(option GetNodesOption) ([]Node, error) {
+	return c.GetNodesContext(context.Background(), option)
+}
+
+// GetNodesContext is the same as GetNodes but accepts a context
+func (c *BlueOceanClient) GetNodesContext(ctx context.Context, option GetNodesOption) ([]Node, error) {
 	var nodes []Node
-	err := c.RequestWithData(http.MethodGet, c.getGetNodesAPI(option), getHeaders(), nil, 200, &nodes)
+	err := c.RequestWithDataContext(ctx, http.MethodGet, c.getGetNodesAPI(option), getHeaders(), nil, 200, &nodes)
 	if err != nil {
 		return nil, err
 	}
@@ -187,8 +236,13 @@ type ReplayOption struct {
 // Replay will queue up a replay of the pipeline run with the same commit id as the run used.
 // Reference: https://github.com/jenkinsci/blueocean-plugin/tree/master/blueocean-rest#replay-a-pipeline-build
 func (c *BlueOceanClient) Replay(option ReplayOption) (*PipelineRun, error) {
+	return c.ReplayContext(context.Background(), option)
+}
+
+// ReplayContext is the same as Replay but accepts a context
+func (c *BlueOceanClient) ReplayContext(ctx context.Context, option ReplayOption) (*PipelineRun, error) {
 	pipelineRun := &PipelineRun{}
-	if err := c.RequestWithData(http.MethodPost, c.getReplayAPI(&option), getHeaders(), nil, 200, pipelineRun); err != nil {
+	if err := c.RequestWithDataContext(ctx, http.MethodPost, c.getReplayAPI(&option), getHeaders(), nil, 200, pipelineRun); err != nil {
 		return nil, err
 	}
 	return pipelineRun, nil
@@ -222,28 +276,53 @@ type GetStepsOption struct {
 // GetSteps returns all steps of the given Pipeline.
 // Reference: https://github.com/jenkinsci/blueocean-plugin/tree/master/blueocean-rest#get-pipeline-steps
 func (c *BlueOceanClient) GetSteps(option GetStepsOption) ([]Step, error) {
+	return c.GetStepsContext(context.Background(), option)
+}
+
+// GetStepsContext is the same as GetSteps but accepts a context
+func (c *BlueOceanClient) GetStepsContext(ctx context.Context, option GetStepsOption) ([]Step, error) {
 	api := c.getGetStepsAPI(&option)
 	steps := make([]Step, 0)
-	if err := c.RequestWithData(http.MethodGet, api, nil, nil, 200, &steps); err != nil {
+	if err := c.RequestWithDataContext(ctx, http.MethodGet, api, nil, nil, 200, &steps); err != nil {
 		return nil, err
 	}
 	return steps, nil
 }
 
 func (c *BlueOceanClient) getGetStepsAPI(option *GetStepsOption) string {
-	// api := c.getGetPipelineAPI(pipelineName, folders...)
-	api := c.getGetPipelineAPI(option.PipelineName, option.Folders...)
-	if option.Branch != "" {
-		api = api + "/branches/" + url.PathEscape(option.Branch)
-	}
-	api = api + "/runs/" + option.RunID
+	api := c.getRunAPI(option.PipelineName, option.Branch, option.RunID, option.Folders...)
 	if option.NodeID != "" {
-		api = api + "/nodes/" + option.NodeID
+		api = api + "/nodes/" + core.EscapePathSegment(option.NodeID)
 	}
 	api = api + "/steps/"
 	return api
 }
 
+// getRunAPI returns the API of a run, it does not end with a slash
+func (c *BlueOceanClient) getRunAPI(pipelineName, branch, runID string, folders ...string) string {
+	api := c.getGetPipelineAPI(pipelineName, folders...)
+	if branch != "" {
+		api = api + "/branches/" + escapeBranch(branch)
+	}
+	return api + "/runs/" + core.EscapePathSegment(runID)
+}
+
+// escapeBranch returns the git branch as a segment of the URL path.
+// It is encoded as the item name first, e.g. feature/foo is feature%2Ffoo, then escaped once more.
+func escapeBranch(branch string) string {
+	return core.EscapePathSegment(core.EncodeBranchName(branch))
+}
+
+// getItemPath returns the classic item path of a Pipeline, or of its branch if there is one.
+// The branch is the git branch name as well, so it addresses the same item as escapeBranch.
+func getItemPath(pipelines []string, branch string) core.ItemPath {
+	itemPath := core.NewItemPath(pipelines...)
+	if branch != "" {
+		itemPath = itemPath.Branch(branch)
+	}
+	return itemPath
+}
+
 // Filter is Pipeline job filter.
 // Reference: https://github.com/jenkinsci/blueocean-plugin/blob/a7cbc946b73d89daf9dfd91cd713cc7ab64a2d95/blueocean-pipeline-api-impl/src/main/java/io/jenkins/blueocean/rest/impl/pipeline/PipelineJobFilters.java
 type Filter string
@@ -268,9 +347,14 @@ type GetBranchesOption struct {
 
 // GetBranches gets branches of a Pipeline.
 func (c *BlueOceanClient) GetBranches(option GetBranchesOption) ([]PipelineBranch, error) {
+	return c.GetBranchesContext(context.Background(), option)
+}
+
+// GetBranchesContext is the same as GetBranches but accepts a context
+func (c *BlueOceanClient) GetBranchesContext(ctx context.Context, option GetBranchesOption) ([]PipelineBranch, error) {
 	api := c.getGetBranchesAPI(&option)
 	branches := []PipelineBranch{}
-	if err := c.RequestWithData(http.MethodGet, api, nil, nil, http.StatusOK, &branches); err != nil {
+	if err := c.RequestWithDataContext(ctx, http.MethodGet, api, nil, nil, http.StatusOK, &branches); err != nil {
 		return nil, err
 	}
 	return branches, nil
@@ -279,10 +363,7 @@ func (c *BlueOceanClient) GetBranches(option GetBranchesOption) ([]PipelineBranc
 func (c *BlueOceanClient) getGetBranchesAPI(option *GetBranchesOption) string {
 	api := c.getGetPipelineAPI(option.PipelineName, option.Folders...)
 	api = api + "/branches/"
-	apiURL := &url.URL{
-		Path: api,
-	}
-	query := apiURL.Query()
+	query := url.Values{}
 	if option.Filter != "" {
 		query.Add("filter", string(option.Filter))
 	}
@@ -292,6 +373,8 @@ func (c *BlueOceanClient) getGetBranchesAPI(option *GetBranchesOption) string {
 	if option.Limit > 0 {
 		query.Add("limit", strconv.Itoa(option.Limit))
 	}
-	apiURL.RawQuery = query.Encode()
-	return apiURL.String()
+	if len(query) > 0 {
+		api = api + "?" + query.Encode()
+	}
+	return api
 }
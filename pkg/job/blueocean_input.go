@@ -0,0 +1,232 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/verystar/jenkins-client/pkg/core"
+)
+
+// pausedState is the state of the nodes and the steps which are waiting for the inputs
+const pausedState = "PAUSED"
+
+// InputValue is the value of a parameter of an input step
+type InputValue struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+	// FilePath is the local file of a file parameter, it is uploaded instead of the Value
+	FilePath string `json:"-"`
+}
+
+// StringValue returns the value of a string or text parameter
+func StringValue(name, value string) InputValue {
+	return InputValue{Name: name, Value: value}
+}
+
+// BooleanValue returns the value of a boolean parameter
+func BooleanValue(name string, value bool) InputValue {
+	return InputValue{Name: name, Value: value}
+}
+
+// ChoiceValue returns the value of a choice parameter
+func ChoiceValue(name, choice string) InputValue {
+	return InputValue{Name: name, Value: choice}
+}
+
+// PasswordValue returns the value of a password parameter
+func PasswordValue(name, password string) InputValue {
+	return InputValue{Name: name, Value: password}
+}
+
+// FileValue returns the value of a file parameter, the file is uploaded when submitting the input
+func FileValue(name, path string) InputValue {
+	return InputValue{Name: name, FilePath: path}
+}
+
+// SubmitInputOption holds options for submitting an input step.
+type SubmitInputOption struct {
+	Folders      []string
+	PipelineName string
+	Branch       string
+	RunID        string
+	// NodeID can be empty if the input step is not in a stage
+	NodeID string
+	StepID string
+	// InputID is the ID of the Input of the step
+	InputID string
+	// Abort aborts the input step, the Parameters are ignored
+	Abort      bool
+	Parameters []InputValue
+}
+
+// SubmitInput proceeds or aborts an input step.
+// The classic input API is used if there are file parameters, since the BlueOcean API accepts JSON only.
+// Reference: https://github.com/jenkinsci/blueocean-plugin/tree/master/blueocean-rest#submit-input-step
+func (c *BlueOceanClient) SubmitInput(option SubmitInputOption) error {
+	return c.SubmitInputContext(context.Background(), option)
+}
+
+// SubmitInputContext is the same as SubmitInput but accepts a context
+func (c *BlueOceanClient) SubmitInputContext(ctx context.Context, option SubmitInputOption) (err error) {
+	payload := map[string]interface{}{"id": option.InputID}
+	if option.Abort {
+		payload["abort"] = true
+	} else {
+		for _, parameter := range option.Parameters {
+			if parameter.FilePath != "" {
+				return c.submitInputWithFiles(ctx, &option)
+			}
+		}
+		parameters := option.Parameters
+		if parameters == nil {
+			parameters = []InputValue{}
+		}
+		payload["parameters"] = parameters
+	}
+
+	var data []byte
+	if data, err = json.Marshal(payload); err == nil {
+		_, err = c.RequestWithoutDataContext(ctx, http.MethodPost, c.getSubmitInputAPI(&option), getHeaders(),
+			bytes.NewReader(data), http.StatusOK)
+	}
+	return
+}
+
+func (c *BlueOceanClient) getSubmitInputAPI(option *SubmitInputOption) string {
+	api := c.getRunAPI(option.PipelineName, option.Branch, option.RunID, option.Folders...)
+	if option.NodeID != "" {
+		api = api + "/nodes/" + core.EscapePathSegment(option.NodeID)
+	}
+	return api + "/steps/" + core.EscapePathSegment(option.StepID) + "/"
+}
+
+// submitInputWithFiles submits the input with a multipart form via the classic input API
+func (c *BlueOceanClient) submitInputWithFiles(ctx context.Context, option *SubmitInputOption) (err error) {
+	var runID int
+	if runID, err = strconv.Atoi(option.RunID); err != nil {
+		return fmt.Errorf("invalid run ID %q: %w", option.RunID, err)
+	}
+	itemPath := getItemPath(append(append([]string{}, option.Folders...), option.PipelineName), option.Branch)
+	api := fmt.Sprintf("%s/%d/input/%s/submit", itemPath, runID, core.EscapePathSegment(option.InputID))
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	parameters := make([]map[string]interface{}, len(option.Parameters))
+	for i, parameter := range option.Parameters {
+		if parameter.FilePath == "" {
+			parameters[i] = map[string]interface{}{"name": parameter.Name, "value": parameter.Value}
+			continue
+		}
+
+		field := fmt.Sprintf("file%d", i)
+		parameters[i] = map[string]interface{}{"name": parameter.Name, "file": field}
+		if err = writeFormFile(writer, field, parameter.FilePath); err != nil {
+			return
+		}
+	}
+
+	var data []byte
+	if data, err = json.Marshal(map[string]interface{}{"parameter": parameters}); err != nil {
+		return
+	}
+	if err = writer.WriteField("json", string(data)); err != nil {
+		return
+	}
+	if err = writer.WriteField("proceed", "Proceed"); err != nil {
+		return
+	}
+	if err = writer.Close(); err != nil {
+		return
+	}
+
+	err = core.NewRequestWithContext(ctx, api, &c.JenkinsCore).WithPostMethod().
+		AddHeader("Content-Type", writer.FormDataContentType()).WithPayload(body).
+		AcceptStatusCode(http.StatusFound).Do()
+	return
+}
+
+func writeFormFile(writer *multipart.Writer, field, path string) (err error) {
+	var file *os.File
+	if file, err = os.Open(path); err != nil {
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var part io.Writer
+	if part, err = writer.CreateFormFile(field, filepath.Base(path)); err == nil {
+		_, err = io.Copy(part, file)
+	}
+	return
+}
+
+// PendingInput is an input step which is waiting for the submission
+type PendingInput struct {
+	// NodeID is empty if the input step is not in a stage
+	NodeID   string
+	NodeName string
+	StepID   string
+	Input    Input
+}
+
+// GetPendingInputsOption holds options for finding the pending inputs of a run.
+type GetPendingInputsOption struct {
+	Folders      []string
+	PipelineName string
+	Branch       string
+	RunID        string
+}
+
+// GetPendingInputs finds the pending input steps in all the nodes of a run
+func (c *BlueOceanClient) GetPendingInputs(option GetPendingInputsOption) ([]PendingInput, error) {
+	return c.GetPendingInputsContext(context.Background(), option)
+}
+
+// GetPendingInputsContext is the same as GetPendingInputs but accepts a context
+func (c *BlueOceanClient) GetPendingInputsContext(ctx context.Context, option GetPendingInputsOption) (
+	inputs []PendingInput, err error) {
+	var nodes []Node
+	if nodes, err = c.GetNodesContext(ctx, GetNodesOption{
+		Pipelines: append(append([]string{}, option.Folders...), option.PipelineName),
+		Branch:    option.Branch,
+		RunID:     option.RunID,
+	}); err != nil {
+		return
+	}
+	if len(nodes) == 0 {
+		// the steps of a Pipeline without stages belong to the run
+		nodes = []Node{{State: pausedState}}
+	}
+
+	for _, node := range nodes {
+		if node.State != pausedState {
+			continue
+		}
+
+		var steps []Step
+		if steps, err = c.GetStepsContext(ctx, GetStepsOption{
+			Folders:      option.Folders,
+			PipelineName: option.PipelineName,
+			Branch:       option.Branch,
+			RunID:        option.RunID,
+			NodeID:       node.ID,
+		}); err != nil {
+			return
+		}
+		for _, step := range steps {
+			if step.Input != nil && step.State == pausedState {
+				inputs = append(inputs, PendingInput{NodeID: node.ID, NodeName: node.DisplayName, StepID: step.ID, Input: *step.Input})
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,174 @@
+package job
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/core"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("BlueOcean input test", func() {
+	var (
+		ctrl         *gomock.Controller
+		c            BlueOceanClient
+		roundTripper *mhttp.MockRoundTripper
+		option       SubmitInputOption
+	)
+
+	const runAPI = "http://localhost/blue/rest/organizations/jenkins/pipelines/folder/pipelines/pipeline/branches/feature%252Fa/runs/1"
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		c = BlueOceanClient{Organization: "jenkins"}
+		c.RoundTripper = roundTripper
+		c.URL = "http://localhost"
+		option = SubmitInputOption{
+			Folders:      []string{"folder"},
+			PipelineName: "pipeline",
+			Branch:       "feature/a",
+			RunID:        "1",
+			NodeID:       "6",
+			StepID:       "7",
+			InputID:      "Approve",
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	givenSubmit := func(body string) {
+		request, _ := http.NewRequest(http.MethodPost, runAPI+"/nodes/6/steps/7/", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		core.PrepareCommonPost(request, "", roundTripper, "", "", c.URL)
+	}
+
+	It("proceed with the typed values", func() {
+		givenSubmit(`{"id":"Approve","parameters":[{"name":"confirm","value":true},{"name":"env","value":"prod"},` +
+			`{"name":"secret","value":"pass"},{"name":"note","value":"ok"}]}`)
+
+		option.Parameters = []InputValue{
+			BooleanValue("confirm", true),
+			ChoiceValue("env", "prod"),
+			PasswordValue("secret", "pass"),
+			StringValue("note", "ok"),
+		}
+		Expect(c.SubmitInput(option)).To(Succeed())
+	})
+
+	It("proceed without parameters", func() {
+		givenSubmit(`{"id":"Approve","parameters":[]}`)
+
+		Expect(c.SubmitInput(option)).To(Succeed())
+	})
+
+	It("abort", func() {
+		givenSubmit(`{"abort":true,"id":"Approve"}`)
+
+		option.Abort = true
+		option.Parameters = []InputValue{StringValue("note", "ignored")}
+		Expect(c.SubmitInput(option)).To(Succeed())
+	})
+
+	It("proceed with a file", func() {
+		file := filepath.Join(GinkgoT().TempDir(), "report.txt")
+		Expect(os.WriteFile(file, []byte("content"), 0600)).To(Succeed())
+
+		core.PrepareForGetIssuer(roundTripper, c.URL, "", "")
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(request *http.Request) (*http.Response, error) {
+			Expect(request.Method).To(Equal(http.MethodPost))
+			Expect(request.URL.String()).To(Equal("http://localhost/job/folder/job/pipeline/job/feature%252Fa/1/input/Approve/submit"))
+			Expect(request.ParseMultipartForm(1024)).To(Succeed())
+			Expect(request.FormValue("proceed")).NotTo(BeEmpty())
+
+			var form map[string][]map[string]interface{}
+			Expect(json.Unmarshal([]byte(request.FormValue("json")), &form)).To(Succeed())
+			Expect(form["parameter"]).To(Equal([]map[string]interface{}{
+				{"name": "note", "value": "ok"},
+				{"name": "report", "file": "file1"},
+			}))
+			uploaded, header, err := request.FormFile("file1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(header.Filename).To(Equal("report.txt"))
+			data, _ := io.ReadAll(uploaded)
+			Expect(string(data)).To(Equal("content"))
+
+			return &http.Response{StatusCode: http.StatusFound, Request: request, Body: io.NopCloser(&bytes.Buffer{})}, nil
+		})
+
+		option.Parameters = []InputValue{StringValue("note", "ok"), FileValue("report", file)}
+		Expect(c.SubmitInput(option)).To(Succeed())
+	})
+
+	It("proceed with a file on the branch from the item name", func() {
+		file := filepath.Join(GinkgoT().TempDir(), "report.txt")
+		Expect(os.WriteFile(file, []byte("content"), 0600)).To(Succeed())
+
+		core.PrepareForGetIssuer(roundTripper, c.URL, "", "")
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(request *http.Request) (*http.Response, error) {
+			Expect(request.URL.String()).To(Equal("http://localhost/job/folder/job/pipeline/job/feature%252Fa/1/input/Approve/submit"))
+			return &http.Response{StatusCode: http.StatusFound, Request: request, Body: io.NopCloser(&bytes.Buffer{})}, nil
+		})
+
+		option.Branch = core.DecodeBranchName("feature%2Fa")
+		option.Parameters = []InputValue{FileValue("report", file)}
+		Expect(c.SubmitInput(option)).To(Succeed())
+	})
+
+	It("proceed with a file on a branch which contains %2F", func() {
+		file := filepath.Join(GinkgoT().TempDir(), "report.txt")
+		Expect(os.WriteFile(file, []byte("content"), 0600)).To(Succeed())
+
+		core.PrepareForGetIssuer(roundTripper, c.URL, "", "")
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(request *http.Request) (*http.Response, error) {
+			Expect(request.URL.String()).To(Equal("http://localhost/job/folder/job/pipeline/job/fix%25252Fbug/1/input/Approve/submit"))
+			return &http.Response{StatusCode: http.StatusFound, Request: request, Body: io.NopCloser(&bytes.Buffer{})}, nil
+		})
+
+		option.Branch = "fix%2Fbug"
+		option.Parameters = []InputValue{FileValue("report", file)}
+		Expect(c.SubmitInput(option)).To(Succeed())
+	})
+
+	It("find the pending inputs", func() {
+		given := func(api, contentType, body string) {
+			request, _ := http.NewRequest(http.MethodGet, api, nil)
+			if contentType != "" {
+				request.Header.Set("Content-Type", contentType)
+			}
+			roundTripper.EXPECT().RoundTrip(core.NewRequestMatcher(request).WithQuery()).Return(&http.Response{
+				StatusCode: http.StatusOK,
+				Request:    request,
+				Body:       io.NopCloser(bytes.NewBufferString(body)),
+			}, nil)
+		}
+		given(runAPI+"/nodes/?limit=10000", "application/json", `[{"id":"5","displayName":"Build","state":"FINISHED"},
+			{"id":"6","displayName":"Deploy","state":"PAUSED","input":{"id":"Approve"}}]`)
+		given(runAPI+"/nodes/6/steps/", "", `[{"id":"8","state":"FINISHED"},
+			{"id":"9","state":"PAUSED","input":{"id":"Approve","message":"Deploy?","ok":"Yes"}}]`)
+
+		inputs, err := c.GetPendingInputs(GetPendingInputsOption{
+			Folders:      []string{"folder"},
+			PipelineName: "pipeline",
+			Branch:       "feature/a",
+			RunID:        "1",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inputs).To(Equal([]PendingInput{{
+			NodeID:   "6",
+			NodeName: "Deploy",
+			StepID:   "9",
+			Input:    Input{ID: "Approve", Message: "Deploy?", Ok: "Yes"},
+		}}))
+	})
+})
@@ -0,0 +1,65 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/verystar/jenkins-client/pkg/core"
+)
+
+// GetLogOption holds options for getting the log of a node or a step.
+type GetLogOption struct {
+	Folders      []string
+	PipelineName string
+	Branch       string
+	RunID        string
+	// NodeID is the stage or the parallel branch, it can be empty if there is a StepID
+	NodeID string
+	// StepID is the step of the node, the log of the whole node is fetched if it is empty
+	StepID string
+}
+
+// GetLog returns a chunk of the log of a node or a step from the start.
+// Reference: https://github.com/jenkinsci/blueocean-plugin/tree/master/blueocean-rest#get-log-for-a-pipeline-step
+func (c *BlueOceanClient) GetLog(option GetLogOption, start int64) (Log, error) {
+	return c.GetLogContext(context.Background(), option, start)
+}
+
+// GetLogContext is the same as GetLog but accepts a context
+func (c *BlueOceanClient) GetLogContext(ctx context.Context, option GetLogOption, start int64) (jobLog Log, err error) {
+	var response *http.Response
+	if response, err = c.RequestWithResponseContext(ctx, http.MethodGet, c.getGetLogAPI(&option, start), nil, nil); err == nil {
+		jobLog, err = readLog(response, start)
+	}
+	return
+}
+
+// FollowLog writes the log of a node or a step until it finishes, then returns the offset of the next start.
+// The HTML of the options is ignored.
+func (c *BlueOceanClient) FollowLog(ctx context.Context, option GetLogOption, w io.Writer, options LogOptions) (int64, error) {
+	return followLog(ctx, w, options, func(ctx context.Context, start int64) (Log, error) {
+		return c.GetLogContext(ctx, option, start)
+	})
+}
+
+// LogReader returns a reader of the log of a node or a step, it follows the log as FollowLog does.
+// The reader must be closed, the following stops once it is closed.
+func (c *BlueOceanClient) LogReader(ctx context.Context, option GetLogOption, options LogOptions) io.ReadCloser {
+	return newLogReader(ctx, func(ctx context.Context, w io.Writer) (err error) {
+		_, err = c.FollowLog(ctx, option, w, options)
+		return
+	})
+}
+
+func (c *BlueOceanClient) getGetLogAPI(option *GetLogOption, start int64) string {
+	api := c.getRunAPI(option.PipelineName, option.Branch, option.RunID, option.Folders...)
+	if option.NodeID != "" {
+		api = api + "/nodes/" + core.EscapePathSegment(option.NodeID)
+	}
+	if option.StepID != "" {
+		api = api + "/steps/" + core.EscapePathSegment(option.StepID)
+	}
+	return fmt.Sprintf("%s/log/?start=%d", api, start)
+}
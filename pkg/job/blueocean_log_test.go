@@ -0,0 +1,99 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/core"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("BlueOcean log test", func() {
+	var (
+		ctrl         *gomock.Controller
+		c            BlueOceanClient
+		roundTripper *mhttp.MockRoundTripper
+		option       GetLogOption
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		c = BlueOceanClient{Organization: "jenkins"}
+		c.RoundTripper = roundTripper
+		c.URL = "http://localhost"
+		option = GetLogOption{
+			Folders:      []string{"folder"},
+			PipelineName: "pipeline",
+			Branch:       "feature/a",
+			RunID:        "1",
+			NodeID:       "6",
+			StepID:       "7",
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	given := func(api string, more bool, size int, body string) {
+		request, _ := http.NewRequest(http.MethodGet, c.URL+api, nil)
+		header := http.Header{"X-Text-Size": {fmt.Sprint(size)}}
+		if more {
+			header.Set("X-More-Data", "true")
+		}
+		roundTripper.EXPECT().RoundTrip(core.NewRequestMatcher(request).WithQuery()).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Request:    request,
+			Header:     header,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+		}, nil)
+	}
+	const stepAPI = "/blue/rest/organizations/jenkins/pipelines/folder/pipelines/pipeline/branches/feature%252Fa/runs/1/nodes/6/steps/7/log/"
+
+	It("get the log of a step", func() {
+		given(stepAPI+"?start=5", true, 11, "hello\n")
+
+		jobLog, err := c.GetLog(option, 5)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobLog).To(Equal(Log{HasMore: true, NextStart: 11, Text: "hello\n"}))
+	})
+
+	It("get the log of a node", func() {
+		option.StepID = ""
+		given("/blue/rest/organizations/jenkins/pipelines/folder/pipelines/pipeline/branches/feature%252Fa/runs/1/nodes/6/log/?start=0",
+			false, 6, "hello\n")
+
+		jobLog, err := c.GetLog(option, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobLog.HasMore).To(BeFalse())
+		Expect(jobLog.Text).To(Equal("hello\n"))
+	})
+
+	It("get the log of a step without a node", func() {
+		option.NodeID, option.Branch, option.Folders = "", "", nil
+		given("/blue/rest/organizations/jenkins/pipelines/pipeline/runs/1/steps/7/log/?start=0", false, 0, "")
+
+		_, err := c.GetLog(option, 0)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("follow the log of a running step", func() {
+		given(stepAPI+"?start=0", true, 6, "hello\n")
+		given(stepAPI+"?start=6", true, 6, "")
+		given(stepAPI+"?start=6", false, 12, "world\n")
+
+		reader := c.LogReader(context.Background(), option, LogOptions{MinInterval: time.Millisecond})
+		data, err := io.ReadAll(reader)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("hello\nworld\n"))
+		Expect(reader.Close()).To(Succeed())
+	})
+})
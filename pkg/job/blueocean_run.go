@@ -0,0 +1,130 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/verystar/jenkins-client/pkg/core"
+)
+
+// StopOption contains some options while stopping a PipelineRun.
+type StopOption struct {
+	Pipelines []string
+	Branch    string
+	RunID     string
+	// Blocking waits until the run stops or the TimeOutInSecs passes
+	Blocking      bool
+	TimeOutInSecs int
+}
+
+// Stop stops a PipelineRun, then returns its latest state.
+// Reference: https://github.com/jenkinsci/blueocean-plugin/tree/master/blueocean-rest#stop-a-build
+func (c *BlueOceanClient) Stop(option StopOption) (*PipelineRun, error) {
+	return c.StopContext(context.Background(), option)
+}
+
+// StopContext is the same as Stop but accepts a context
+func (c *BlueOceanClient) StopContext(ctx context.Context, option StopOption) (*PipelineRun, error) {
+	var pr PipelineRun
+	err := c.RequestWithDataContext(ctx, http.MethodPut, c.getStopAPI(&option), getHeaders(), nil, 200, &pr)
+	if err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+func (c *BlueOceanClient) getStopAPI(option *StopOption) string {
+	api := c.getGetBuildAPI(GetBuildOption{
+		Pipelines: option.Pipelines,
+		Branch:    option.Branch,
+		RunID:     option.RunID,
+	})
+	query := url.Values{}
+	if option.Blocking {
+		query.Set("blocking", "true")
+	}
+	if option.TimeOutInSecs > 0 {
+		query.Set("timeOutInSecs", strconv.Itoa(option.TimeOutInSecs))
+	}
+	api = api + "stop/"
+	if len(query) > 0 {
+		api = api + "?" + query.Encode()
+	}
+	return api
+}
+
+// RestartStageOption contains some options while restarting a stage of a declarative Pipeline.
+type RestartStageOption struct {
+	Pipelines []string
+	Branch    string
+	RunID     string
+	// NodeID is the stage, its Restartable must be true
+	NodeID string
+}
+
+// RestartStage restarts a PipelineRun from a stage, then returns the queue item of the new run.
+// Only the top level stages of a finished declarative Pipeline can be restarted.
+func (c *BlueOceanClient) RestartStage(option RestartStageOption) (*BlueQueueItem, error) {
+	return c.RestartStageContext(context.Background(), option)
+}
+
+// RestartStageContext is the same as RestartStage but accepts a context
+func (c *BlueOceanClient) RestartStageContext(ctx context.Context, option RestartStageOption) (*BlueQueueItem, error) {
+	api := c.getGetBuildAPI(GetBuildOption{
+		Pipelines: option.Pipelines,
+		Branch:    option.Branch,
+		RunID:     option.RunID,
+	})
+	api = fmt.Sprintf("%snodes/%s/restart/", api, core.EscapePathSegment(option.NodeID))
+
+	var item BlueQueueItem
+	err := c.RequestWithDataContext(ctx, http.MethodPost, api, getHeaders(), strings.NewReader(`{"restart":true}`), 200, &item)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetChangeSet returns the commits of a PipelineRun
+func (c *BlueOceanClient) GetChangeSet(option GetBuildOption) ([]BlueChangeSetEntry, error) {
+	return c.GetChangeSetContext(context.Background(), option)
+}
+
+// GetChangeSetContext is the same as GetChangeSet but accepts a context
+func (c *BlueOceanClient) GetChangeSetContext(ctx context.Context, option GetBuildOption) (changeSet []BlueChangeSetEntry, err error) {
+	err = c.RequestWithDataContext(ctx, http.MethodGet, c.getGetBuildAPI(option)+"changeSet/", getHeaders(), nil, 200, &changeSet)
+	return
+}
+
+// GetArtifacts returns the artifacts of a PipelineRun, the BlueOcean API returns 100 artifacts at most.
+// See also ArtifactsPager.
+func (c *BlueOceanClient) GetArtifacts(option GetBuildOption) ([]BlueArtifact, error) {
+	return c.GetArtifactsContext(context.Background(), option)
+}
+
+// GetArtifactsContext is the same as GetArtifacts but accepts a context
+func (c *BlueOceanClient) GetArtifactsContext(ctx context.Context, option GetBuildOption) (artifacts []BlueArtifact, err error) {
+	err = c.RequestWithDataContext(ctx, http.MethodGet, c.getGetBuildAPI(option)+"artifacts/", getHeaders(), nil, 200, &artifacts)
+	return
+}
+
+// DeleteRun deletes a PipelineRun via the classic API, since there is no such BlueOcean API
+func (c *BlueOceanClient) DeleteRun(option GetBuildOption) error {
+	return c.DeleteRunContext(context.Background(), option)
+}
+
+// DeleteRunContext is the same as DeleteRun but accepts a context
+func (c *BlueOceanClient) DeleteRunContext(ctx context.Context, option GetBuildOption) (err error) {
+	var runID int
+	if runID, err = strconv.Atoi(option.RunID); err != nil || runID < 1 {
+		return fmt.Errorf("invalid run ID %q", option.RunID)
+	}
+	api := getItemPath(option.Pipelines, option.Branch).Run(runID) + "/doDelete"
+	err = core.NewRequestWithContext(ctx, api, &c.JenkinsCore).
+		AsPostFormRequest().AcceptStatusCode(http.StatusFound).Do()
+	return
+}
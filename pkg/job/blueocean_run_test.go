@@ -0,0 +1,161 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/core"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("BlueOcean run test", func() {
+	var (
+		ctrl         *gomock.Controller
+		c            BlueOceanClient
+		roundTripper *mhttp.MockRoundTripper
+		option       GetBuildOption
+	)
+
+	const runAPI = "http://localhost/blue/rest/organizations/jenkins/pipelines/folder/pipelines/pipeline/branches/feature%252Fa/runs/1/"
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		c = BlueOceanClient{Organization: "jenkins"}
+		c.RoundTripper = roundTripper
+		c.URL = "http://localhost"
+		option = GetBuildOption{
+			Pipelines: []string{"folder", "pipeline"},
+			Branch:    "feature/a",
+			RunID:     "1",
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	givenGet := func(api, body string) {
+		request, _ := http.NewRequest(http.MethodGet, api, nil)
+		request.Header.Set("Content-Type", "application/json")
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Request:    request,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+		}
+		roundTripper.EXPECT().RoundTrip(core.NewRequestMatcher(request).WithQuery()).Return(response, nil)
+	}
+
+	It("stop a run", func() {
+		givenStop := func(api string) {
+			request, _ := http.NewRequest(http.MethodPut, api, nil)
+			request.Header.Set("Content-Type", "application/json")
+			response := &http.Response{
+				StatusCode: http.StatusOK,
+				Request:    request,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"id":"1","state":"FINISHED","result":"ABORTED"}`)),
+			}
+			roundTripper.EXPECT().RoundTrip(core.NewRequestMatcher(request).WithQuery()).Return(response, nil)
+		}
+		givenStop(runAPI + "stop/")
+		givenStop(runAPI + "stop/?blocking=true&timeOutInSecs=10")
+
+		stopOption := StopOption{Pipelines: option.Pipelines, Branch: option.Branch, RunID: option.RunID}
+		run, err := c.Stop(stopOption)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(run.Result).To(Equal("ABORTED"))
+
+		stopOption.Blocking, stopOption.TimeOutInSecs = true, 10
+		run, err = c.Stop(stopOption)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(run.State).To(Equal("FINISHED"))
+	})
+
+	It("restart a stage", func() {
+		request, _ := http.NewRequest(http.MethodPost, runAPI+"nodes/6/restart/", strings.NewReader(`{"restart":true}`))
+		request.Header.Set("Content-Type", "application/json")
+		core.PrepareCommonPost(request, `{"id":"12","expectedBuildNumber":2,"pipeline":"feature%2Fa"}`, roundTripper, "", "", c.URL)
+
+		item, err := c.RestartStage(RestartStageOption{
+			Pipelines: option.Pipelines,
+			Branch:    option.Branch,
+			RunID:     option.RunID,
+			NodeID:    "6",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.ID).To(Equal("12"))
+		Expect(item.ExpectedBuildNumber).To(Equal(2))
+	})
+
+	It("get the change set", func() {
+		givenGet(runAPI+"changeSet/", `[{"commitId":"abc","msg":"fix","author":{"id":"alice"}}]`)
+
+		changeSet, err := c.GetChangeSet(option)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changeSet).To(HaveLen(1))
+		Expect(changeSet[0].CommitID).To(Equal("abc"))
+	})
+
+	It("get the artifacts", func() {
+		givenGet(runAPI+"artifacts/", `[{"name":"app.jar","path":"target/app.jar","size":10}]`)
+
+		artifacts, err := c.GetArtifacts(option)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(artifacts).To(HaveLen(1))
+		Expect(artifacts[0].Path).To(Equal("target/app.jar"))
+	})
+
+	It("walk through the artifacts", func() {
+		request, _ := http.NewRequest(http.MethodGet, runAPI+"artifacts/?limit=2&start=0", nil)
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Request:    request,
+			Body:       io.NopCloser(bytes.NewBufferString(`[{"name":"app.jar"}]`)),
+		}
+		roundTripper.EXPECT().RoundTrip(core.NewRequestMatcher(request).WithQuery()).Return(response, nil)
+
+		artifacts, err := c.ArtifactsPager(option, 2).Collect(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(artifacts).To(HaveLen(1))
+	})
+
+	It("delete a run", func() {
+		request, _ := http.NewRequest(http.MethodPost,
+			"http://localhost/job/folder/job/pipeline/job/feature%252Fa/1/doDelete", nil)
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		core.PrepareCommonPostWithResponseCode(request, "", http.StatusFound, roundTripper, "", "", c.URL)
+
+		Expect(c.DeleteRun(option)).To(Succeed())
+	})
+
+	It("delete a run on the branch from the item name", func() {
+		request, _ := http.NewRequest(http.MethodPost,
+			"http://localhost/job/folder/job/pipeline/job/feature%252Fa/1/doDelete", nil)
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		core.PrepareCommonPostWithResponseCode(request, "", http.StatusFound, roundTripper, "", "", c.URL)
+
+		option.Branch = core.DecodeBranchName("feature%2Fa")
+		Expect(c.DeleteRun(option)).To(Succeed())
+	})
+
+	It("delete a run on a branch which contains %2F", func() {
+		request, _ := http.NewRequest(http.MethodPost,
+			"http://localhost/job/folder/job/pipeline/job/fix%25252Fbug/1/doDelete", nil)
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		core.PrepareCommonPostWithResponseCode(request, "", http.StatusFound, roundTripper, "", "", c.URL)
+
+		option.Branch = "fix%2Fbug"
+		Expect(c.DeleteRun(option)).To(Succeed())
+	})
+
+	It("delete a run with an invalid ID", func() {
+		option.RunID = "last"
+		Expect(c.DeleteRun(option)).To(MatchError(`invalid run ID "last"`))
+	})
+})
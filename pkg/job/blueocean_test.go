@@ -710,7 +710,7 @@ var _ = Describe("SimplePipeline test via BlueOcean RESTful API", func() {
 			option := GetStepsOption{
 				RunID:        "123",
 				PipelineName: "pipelineA",
-				Branch:       "release%2Fv3.2",
+				Branch:       "release/v3.2",
 			}
 			given("/blue/rest/organizations/jenkins/pipelines/pipelineA/branches/release%252Fv3.2/runs/123/steps/", 200, "[]")
 			steps, err := c.GetSteps(option)
@@ -718,6 +718,26 @@ var _ = Describe("SimplePipeline test via BlueOcean RESTful API", func() {
 			Expect(steps).NotTo(BeNil())
 			Expect(len(steps)).To(Equal(0))
 		})
+		It("With a branch name which looks like an item name", func() {
+			option := GetStepsOption{
+				RunID:        "123",
+				PipelineName: "pipelineA",
+				Branch:       "fix%2Fbug",
+			}
+			given("/blue/rest/organizations/jenkins/pipelines/pipelineA/branches/fix%25252Fbug/runs/123/steps/", 200, "[]")
+			_, err := c.GetSteps(option)
+			Expect(err).To(Succeed())
+		})
+		It("With the branch item name from a response", func() {
+			option := GetStepsOption{
+				RunID:        "123",
+				PipelineName: "pipelineA",
+				Branch:       core.DecodeBranchName("release%2Fv3.2"),
+			}
+			given("/blue/rest/organizations/jenkins/pipelines/pipelineA/branches/release%252Fv3.2/runs/123/steps/", 200, "[]")
+			_, err := c.GetSteps(option)
+			Expect(err).To(Succeed())
+		})
 		It("With one folder and branch", func() {
 			option := GetStepsOption{
 				RunID:        "123",
@@ -808,6 +828,14 @@ var _ = Describe("SimplePipeline test via BlueOcean RESTful API", func() {
 			Expect(branches).NotTo(BeNil())
 			Expect(len(branches)).To(Equal(0))
 		})
+		It("With special characters in the names", func() {
+			api := c.getGetBranchesAPI(&GetBranchesOption{
+				Folders:      []string{"folder 1"},
+				PipelineName: "a#b",
+				Limit:        10,
+			})
+			Expect(api).To(Equal("/blue/rest/organizations/jenkins/pipelines/folder%201/pipelines/a%23b/branches/?limit=10"))
+		})
 		It("Response a branch", func() {
 			given("/blue/rest/organizations/jenkins/pipelines/pipelineA/branches/", http.StatusOK, `
 [{
@@ -916,7 +944,7 @@ func TestBlueOceanClient_getBuildAPI(t *testing.T) {
 				Branch:    "feature/a",
 			},
 		},
-		want: "/blue/rest/organizations/jenkins/pipelines/pipelineA/branches/feature%2Fa/runs/",
+		want: "/blue/rest/organizations/jenkins/pipelines/pipelineA/branches/feature%252Fa/runs/",
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -981,7 +1009,7 @@ func TestBlueOceanClient_getGetBuildAPI(t *testing.T) {
 				RunID:     "123",
 			},
 		},
-		want: "/blue/rest/organizations/jenkins/pipelines/pipelineA/branches/feature%2Fa/runs/123/",
+		want: "/blue/rest/organizations/jenkins/pipelines/pipelineA/branches/feature%252Fa/runs/123/",
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -242,3 +242,27 @@ type PipelineBranch struct {
 	Branch      *Branch      `json:"branch,omitempty"`
 	PullRequest *PullRequest `json:"pullRequest,omitempty"`
 }
+
+// BlueQueueItem is a queued run of a Pipeline.
+// Reference: https://github.com/jenkinsci/blueocean-plugin/blob/6b27be3724c892427b732f30575fdcc2977cfaef/blueocean-rest/src/main/java/io/jenkins/blueocean/rest/model/BlueQueueItem.java
+type BlueQueueItem struct {
+	ID                  string `json:"id,omitempty"`
+	Organization        string `json:"organization,omitempty"`
+	Pipeline            string `json:"pipeline,omitempty"`
+	QueuedTime          Time   `json:"queuedTime,omitempty"`
+	ExpectedBuildNumber int    `json:"expectedBuildNumber,omitempty"`
+	CauseOfBlockage     string `json:"causeOfBlockage,omitempty"`
+}
+
+// BlueOrganization is an organization of the BlueOcean API.
+// Reference: https://github.com/jenkinsci/blueocean-plugin/blob/6b27be3724c892427b732f30575fdcc2977cfaef/blueocean-rest/src/main/java/io/jenkins/blueocean/rest/model/BlueOrganization.java
+type BlueOrganization struct {
+	Name        string `json:"name,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// BlueFavorite is a favorite Pipeline or branch of a user.
+// Reference: https://github.com/jenkinsci/blueocean-plugin/blob/6b27be3724c892427b732f30575fdcc2977cfaef/blueocean-rest/src/main/java/io/jenkins/blueocean/rest/model/BlueFavorite.java
+type BlueFavorite struct {
+	Item *PipelineBranch `json:"item,omitempty"`
+}
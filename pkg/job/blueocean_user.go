@@ -0,0 +1,107 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/verystar/jenkins-client/pkg/core"
+)
+
+const usersAPIPrefix = "/blue/rest/users"
+
+// GetOrganizations returns all the organizations
+func (c *BlueOceanClient) GetOrganizations() ([]BlueOrganization, error) {
+	return c.GetOrganizationsContext(context.Background())
+}
+
+// GetOrganizationsContext is the same as GetOrganizations but accepts a context
+func (c *BlueOceanClient) GetOrganizationsContext(ctx context.Context) (organizations []BlueOrganization, err error) {
+	err = c.RequestWithDataContext(ctx, http.MethodGet, organizationAPIPrefix+"/", getHeaders(), nil, 200, &organizations)
+	return
+}
+
+// GetCurrentUser returns the current user with the permissions in the Organization
+func (c *BlueOceanClient) GetCurrentUser() (*BlueUser, error) {
+	return c.GetCurrentUserContext(context.Background())
+}
+
+// GetCurrentUserContext is the same as GetCurrentUser but accepts a context
+func (c *BlueOceanClient) GetCurrentUserContext(ctx context.Context) (*BlueUser, error) {
+	var user BlueUser
+	if err := c.RequestWithDataContext(ctx, http.MethodGet, c.getOrganizationAPI()+"/user/", getHeaders(), nil, 200, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUsers returns the users of the Organization
+func (c *BlueOceanClient) GetUsers() ([]BlueUser, error) {
+	return c.GetUsersContext(context.Background())
+}
+
+// GetUsersContext is the same as GetUsers but accepts a context
+func (c *BlueOceanClient) GetUsersContext(ctx context.Context) (users []BlueUser, err error) {
+	err = c.RequestWithDataContext(ctx, http.MethodGet, c.getOrganizationAPI()+"/users/", getHeaders(), nil, 200, &users)
+	return
+}
+
+// GetUser returns a user of the Organization
+func (c *BlueOceanClient) GetUser(userID string) (*BlueUser, error) {
+	return c.GetUserContext(context.Background(), userID)
+}
+
+// GetUserContext is the same as GetUser but accepts a context
+func (c *BlueOceanClient) GetUserContext(ctx context.Context, userID string) (*BlueUser, error) {
+	api := fmt.Sprintf("%s/users/%s/", c.getOrganizationAPI(), core.EscapePathSegment(userID))
+	var user BlueUser
+	if err := c.RequestWithDataContext(ctx, http.MethodGet, api, getHeaders(), nil, 200, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetFavorites returns the favorites of a user, the BlueOcean API returns 100 favorites at most.
+// See also FavoritesPager.
+func (c *BlueOceanClient) GetFavorites(userID string) ([]BlueFavorite, error) {
+	return c.GetFavoritesContext(context.Background(), userID)
+}
+
+// GetFavoritesContext is the same as GetFavorites but accepts a context
+func (c *BlueOceanClient) GetFavoritesContext(ctx context.Context, userID string) (favorites []BlueFavorite, err error) {
+	err = c.RequestWithDataContext(ctx, http.MethodGet, getFavoritesAPI(userID), getHeaders(), nil, 200, &favorites)
+	return
+}
+
+// FavoriteOption contains some options while marking a Pipeline as a favorite of the current user.
+type FavoriteOption struct {
+	Pipelines []string
+	// Branch is the default branch of a multi-branch Pipeline if it is empty
+	Branch   string
+	Favorite bool
+}
+
+// SetFavorite adds a Pipeline or a branch to the favorites of the current user, or removes it
+func (c *BlueOceanClient) SetFavorite(option FavoriteOption) error {
+	return c.SetFavoriteContext(context.Background(), option)
+}
+
+// SetFavoriteContext is the same as SetFavorite but accepts a context
+func (c *BlueOceanClient) SetFavoriteContext(ctx context.Context, option FavoriteOption) (err error) {
+	api := fmt.Sprintf("%s/%s", c.getOrganizationAPI(), parsePipelinePath(option.Pipelines))
+	if option.Branch != "" {
+		api = fmt.Sprintf("%s/branches/%s", api, escapeBranch(option.Branch))
+	}
+	payload := strings.NewReader(fmt.Sprintf(`{"favorite":%t}`, option.Favorite))
+	_, err = c.RequestWithoutDataContext(ctx, http.MethodPut, api+"/favorite", getHeaders(), payload, 200)
+	return
+}
+
+func (c *BlueOceanClient) getOrganizationAPI() string {
+	return fmt.Sprintf("%s/%s", organizationAPIPrefix, core.EscapePathSegment(c.Organization))
+}
+
+func getFavoritesAPI(userID string) string {
+	return fmt.Sprintf("%s/%s/favorites/", usersAPIPrefix, core.EscapePathSegment(userID))
+}
@@ -0,0 +1,131 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/core"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("BlueOcean user test", func() {
+	var (
+		ctrl         *gomock.Controller
+		c            BlueOceanClient
+		roundTripper *mhttp.MockRoundTripper
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		c = BlueOceanClient{Organization: "jenkins"}
+		c.RoundTripper = roundTripper
+		c.URL = "http://localhost"
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	given := func(method, api, payload, body string, code int) {
+		var request *http.Request
+		if payload == "" {
+			request, _ = http.NewRequest(method, c.URL+api, nil)
+		} else {
+			request, _ = http.NewRequest(method, c.URL+api, strings.NewReader(payload))
+		}
+		request.Header.Set("Content-Type", "application/json")
+		response := &http.Response{
+			StatusCode: code,
+			Request:    request,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+		}
+		roundTripper.EXPECT().RoundTrip(core.NewRequestMatcher(request).WithQuery().WithBody()).Return(response, nil)
+	}
+
+	It("get the organizations", func() {
+		given(http.MethodGet, "/blue/rest/organizations/", "", `[{"name":"jenkins","displayName":"Jenkins"}]`, http.StatusOK)
+
+		organizations, err := c.GetOrganizations()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(organizations).To(Equal([]BlueOrganization{{Name: "jenkins", DisplayName: "Jenkins"}}))
+	})
+
+	It("get the current user with the permissions", func() {
+		given(http.MethodGet, "/blue/rest/organizations/jenkins/user/", "",
+			`{"id":"alice","fullName":"Alice","permission":{"administrator":true,"pipeline":{"create":true,"start":false}}}`,
+			http.StatusOK)
+
+		user, err := c.GetCurrentUser()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(user.ID).To(Equal("alice"))
+		Expect(user.Permission.Administrator).To(BeTrue())
+		Expect(*user.Permission.PipelinePermissions).To(Equal(Permissions{"create": true, "start": false}))
+	})
+
+	It("get the current user as anonymous", func() {
+		given(http.MethodGet, "/blue/rest/organizations/jenkins/user/", "", "", http.StatusNotFound)
+
+		_, err := c.GetCurrentUser()
+		Expect(core.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("get the users", func() {
+		given(http.MethodGet, "/blue/rest/organizations/jenkins/users/", "", `[{"id":"alice"},{"id":"bob"}]`, http.StatusOK)
+		given(http.MethodGet, "/blue/rest/organizations/jenkins/users/bob/", "", `{"id":"bob","email":"bob@example.com"}`, http.StatusOK)
+
+		users, err := c.GetUsers()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(users).To(HaveLen(2))
+
+		user, err := c.GetUser("bob")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(user.Email).To(Equal("bob@example.com"))
+	})
+
+	It("get the favorites", func() {
+		given(http.MethodGet, "/blue/rest/users/alice/favorites/", "",
+			`[{"item":{"name":"master","fullName":"folder/repo/master","latestRun":{"id":"3","result":"FAILURE"}}}]`,
+			http.StatusOK)
+
+		favorites, err := c.GetFavorites("alice")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(favorites).To(HaveLen(1))
+		Expect(favorites[0].Item.FullName).To(Equal("folder/repo/master"))
+		Expect(favorites[0].Item.LatestRun.Result).To(Equal("FAILURE"))
+	})
+
+	It("walk through the favorites", func() {
+		request, _ := http.NewRequest(http.MethodGet, c.URL+"/blue/rest/users/alice/favorites/?limit=10&start=0", nil)
+		response := &http.Response{
+			StatusCode: http.StatusOK,
+			Request:    request,
+			Body:       io.NopCloser(bytes.NewBufferString(`[{"item":{"name":"repo"}}]`)),
+		}
+		roundTripper.EXPECT().RoundTrip(core.NewRequestMatcher(request).WithQuery()).Return(response, nil)
+
+		favorites, err := c.FavoritesPager("alice", 10).Collect(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(favorites).To(HaveLen(1))
+	})
+
+	It("set a favorite", func() {
+		given(http.MethodPut, "/blue/rest/organizations/jenkins/pipelines/folder/pipelines/repo/branches/feature%252Fa/favorite",
+			`{"favorite":true}`, `{"item":{"name":"feature%2Fa"}}`, http.StatusOK)
+		given(http.MethodPut, "/blue/rest/organizations/jenkins/pipelines/folder/pipelines/repo/favorite",
+			`{"favorite":false}`, "", http.StatusOK)
+
+		Expect(c.SetFavorite(FavoriteOption{
+			Pipelines: []string{"folder", "repo"},
+			Branch:    "feature/a",
+			Favorite:  true,
+		})).To(Succeed())
+		Expect(c.SetFavorite(FavoriteOption{Pipelines: []string{"folder", "repo"}})).To(Succeed())
+	})
+})
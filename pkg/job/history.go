@@ -0,0 +1,182 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/verystar/jenkins-client/pkg/core"
+)
+
+// DefaultHistoryWorkers is the count of the concurrent requests when fetching the builds one by one
+const DefaultHistoryWorkers = 4
+
+// historyLimit is the count of the builds of GetHistory, it is the same as the builds field of a job
+const historyLimit = 100
+
+// historyPageSize is the count of the builds in a request when filtering the builds by the start time
+const historyPageSize = 100
+
+// HistoryOptions is the filters of the build history, the builds are ordered from the latest one
+type HistoryOptions struct {
+	// From is the index of the first build, the latest build is 0
+	From int
+	// To is the exclusive index of the last build, zero means no limit
+	To int
+	// Limit is the max count of the builds, zero means no limit
+	Limit int
+	// Since ignores the builds which started before it, the builds after the first such one are ignored as well
+	Since time.Time
+	// Workers is the count of the concurrent requests, DefaultHistoryWorkers is used if it is not positive
+	Workers int
+}
+
+// allBuilds is nil if the Jenkins does not support the allBuilds tree
+type allBuilds struct {
+	AllBuilds []*Build
+}
+
+// to returns the exclusive index of the last build, a negative value means no limit
+func (o HistoryOptions) to() int {
+	to := o.To
+	if to <= 0 {
+		to = -1
+	}
+	if o.Limit > 0 && (to < 0 || o.From+o.Limit < to) {
+		to = o.From + o.Limit
+	}
+	return to
+}
+
+func (o HistoryOptions) filter(builds []*Build) []*Build {
+	if o.Since.IsZero() {
+		return builds
+	}
+	since := o.Since.UnixMilli()
+	filtered := builds[:0]
+	for _, build := range builds {
+		if build.Timestamp >= since {
+			filtered = append(filtered, build)
+		}
+	}
+	return filtered
+}
+
+// ListBuilds returns the builds of a job which match the options
+func (q *Client) ListBuilds(name string, options HistoryOptions) (builds []*Build, err error) {
+	return q.ListBuildsContext(context.Background(), name, options)
+}
+
+// ListBuildsContext is the same as ListBuilds but accepts a context.
+// All the builds are fetched in one request of the allBuilds tree. If Since is set, the builds are fetched
+// in pages of 100 from the latest one until the first build which started before Since.
+// If the Jenkins does not support the allBuilds tree, the builds are fetched one by one concurrently,
+// then the ones fetched successfully are returned together with the joined errors of the others.
+// Only the latest 100 builds can be found in this case.
+func (q *Client) ListBuildsContext(ctx context.Context, name string, options HistoryOptions) (builds []*Build, err error) {
+	from, to := options.From, options.to()
+	if options.Since.IsZero() {
+		builds, err = q.getAllBuilds(ctx, name, from, to)
+	} else {
+		builds, err = q.getAllBuildsSince(ctx, name, options.Since, from, to)
+	}
+	if err != nil || builds != nil {
+		return
+	}
+
+	var job *Job
+	if job, err = q.GetJobContext(ctx, name); err != nil {
+		return
+	}
+	var numbers []int
+	for i, build := range job.Builds {
+		if i >= from && (to < 0 || i < to) {
+			numbers = append(numbers, build.Number)
+		}
+	}
+	builds, err = q.getBuilds(ctx, name, numbers, options.Workers)
+	builds = options.filter(builds)
+	return
+}
+
+// getAllBuilds returns the builds in [from, to) of the allBuilds tree, it is nil if the Jenkins does not support it
+func (q *Client) getAllBuilds(ctx context.Context, name string, from, to int) (builds []*Build, err error) {
+	tree := core.NewTree().Add("allBuilds", core.TreeFor[Build]())
+	if from > 0 || to >= 0 {
+		tree.Range("allBuilds", from, to)
+	}
+
+	var history allBuilds
+	if history, err = core.GetAPI[allBuilds](ctx, &q.JenkinsCore, ParseJobPath(name), core.APIOptions{Tree: tree}); err == nil {
+		builds = history.AllBuilds
+	}
+	return
+}
+
+// getAllBuildsSince pages through the allBuilds tree until the first build which started before since.
+// The builds are nil if the Jenkins does not support the allBuilds tree.
+func (q *Client) getAllBuildsSince(ctx context.Context, name string, since time.Time, from, to int) (builds []*Build, err error) {
+	for pageFrom := from; to < 0 || pageFrom < to; pageFrom += historyPageSize {
+		pageTo := pageFrom + historyPageSize
+		if to >= 0 && pageTo > to {
+			pageTo = to
+		}
+
+		var page []*Build
+		if page, err = q.getAllBuilds(ctx, name, pageFrom, pageTo); err != nil || page == nil {
+			return
+		}
+		if builds == nil {
+			// not nil since the allBuilds tree is supported
+			builds = make([]*Build, 0, len(page))
+		}
+		for _, build := range page {
+			if build.Timestamp < since.UnixMilli() {
+				return
+			}
+			builds = append(builds, build)
+		}
+		if len(page) < pageTo-pageFrom {
+			return
+		}
+	}
+	return
+}
+
+// getBuilds fetches the builds concurrently, the failed ones are skipped and their errors are joined
+func (q *Client) getBuilds(ctx context.Context, name string, numbers []int, workers int) (builds []*Build, err error) {
+	if workers <= 0 {
+		workers = DefaultHistoryWorkers
+	}
+	results := make([]*Build, len(numbers))
+	errs := make([]error, len(numbers))
+	indexes := make(chan int)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers && i < len(numbers); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				if results[index], errs[index] = q.GetBuildContext(ctx, name, numbers[index]); errs[index] != nil {
+					errs[index] = fmt.Errorf("failed to get build #%d: %w", numbers[index], errs[index])
+				}
+			}
+		}()
+	}
+	for i := range numbers {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, build := range results {
+		if errs[i] == nil && build != nil {
+			builds = append(builds, build)
+		}
+	}
+	err = errors.Join(errs...)
+	return
+}
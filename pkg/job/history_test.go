@@ -0,0 +1,151 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/core"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("history test", func() {
+	var (
+		ctrl         *gomock.Controller
+		jobClient    Client
+		roundTripper *mhttp.MockRoundTripper
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		jobClient = Client{}
+		jobClient.RoundTripper = roundTripper
+		jobClient.URL = "http://localhost"
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	given := func(api string, query url.Values, code int, body string) {
+		if query != nil {
+			api += "?" + query.Encode()
+		}
+		request, _ := http.NewRequest(http.MethodGet, jobClient.URL+api, nil)
+		roundTripper.EXPECT().RoundTrip(core.NewRequestMatcher(request).WithQuery()).Return(&http.Response{
+			StatusCode: code,
+			Request:    request,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+		}, nil)
+	}
+	jobTree := core.TreeFor[Job]().Set("builds", core.NewTree("number", "url")).String()
+	allBuildsTree := func(span string) url.Values {
+		return url.Values{"tree": {"allBuilds[" + core.TreeFor[Build]().String() + "]" + span}}
+	}
+
+	It("fetch the builds in one request", func() {
+		given("/job/fake/api/json", allBuildsTree("{1,3}"), http.StatusOK,
+			`{"allBuilds":[{"number":9,"timestamp":2000},{"number":8,"timestamp":1000}]}`)
+
+		builds, err := jobClient.ListBuilds("fake", HistoryOptions{From: 1, To: 10, Limit: 2})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(builds).To(HaveLen(2))
+		Expect(builds[0].Number).To(Equal(9))
+	})
+
+	It("filter the builds by the start time", func() {
+		given("/job/fake/api/json", allBuildsTree("{0,100}"), http.StatusOK,
+			`{"allBuilds":[{"number":9,"timestamp":2000},{"number":8,"timestamp":1000}]}`)
+
+		builds, err := jobClient.ListBuilds("fake", HistoryOptions{Since: time.UnixMilli(1500)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(builds).To(HaveLen(1))
+		Expect(builds[0].Number).To(Equal(9))
+	})
+
+	It("page through the builds until the start time", func() {
+		page := func(latest, count int) string {
+			builds := make([]string, count)
+			for i := range builds {
+				builds[i] = fmt.Sprintf(`{"number":%d,"timestamp":%d}`, latest-i, (latest-i)*1000)
+			}
+			return `{"allBuilds":[` + strings.Join(builds, ",") + `]}`
+		}
+		given("/job/fake/api/json", allBuildsTree("{0,100}"), http.StatusOK, page(300, 100))
+		given("/job/fake/api/json", allBuildsTree("{100,200}"), http.StatusOK, page(200, 100))
+
+		builds, err := jobClient.ListBuilds("fake", HistoryOptions{Since: time.UnixMilli(150 * 1000)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(builds).To(HaveLen(151))
+		Expect(builds[150].Number).To(Equal(150))
+	})
+
+	It("page through the builds within the limit", func() {
+		given("/job/fake/api/json", allBuildsTree("{0,2}"), http.StatusOK,
+			`{"allBuilds":[{"number":9,"timestamp":2000},{"number":8,"timestamp":1000}]}`)
+
+		builds, err := jobClient.ListBuilds("fake", HistoryOptions{Limit: 2, Since: time.UnixMilli(500)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(builds).To(HaveLen(2))
+	})
+
+	It("filter the builds by the start time one by one", func() {
+		given("/job/fake/api/json", allBuildsTree("{0,100}"), http.StatusOK, `{}`)
+		given("/job/fake/api/json", url.Values{"tree": {jobTree}}, http.StatusOK,
+			`{"builds":[{"number":2},{"number":1}]}`)
+		given("/job/fake/2/api/json", nil, http.StatusOK, `{"number":2,"timestamp":2000}`)
+		given("/job/fake/1/api/json", nil, http.StatusOK, `{"number":1,"timestamp":1000}`)
+
+		builds, err := jobClient.ListBuilds("fake", HistoryOptions{Since: time.UnixMilli(1500), Workers: 1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(builds).To(HaveLen(1))
+		Expect(builds[0].Number).To(Equal(2))
+	})
+
+	It("fall back to fetch the builds one by one", func() {
+		given("/job/fake/api/json", allBuildsTree("{0,2}"), http.StatusOK, `{"_class":"hudson.model.FreeStyleProject"}`)
+		given("/job/fake/api/json", url.Values{"tree": {jobTree}}, http.StatusOK,
+			`{"builds":[{"number":3},{"number":2},{"number":1}]}`)
+		given("/job/fake/3/api/json", nil, http.StatusOK, `{"number":3}`)
+		given("/job/fake/2/api/json", nil, http.StatusInternalServerError, `failed`)
+
+		builds, err := jobClient.ListBuildsContext(context.Background(), "fake", HistoryOptions{Limit: 2, Workers: 1})
+		Expect(builds).To(HaveLen(1))
+		Expect(builds[0].Number).To(Equal(3))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed to get build #2"))
+		Expect(core.IsServerError(err)).To(BeTrue())
+	})
+
+	It("return the partial history", func() {
+		given("/job/fake/api/json", allBuildsTree("{0,100}"), http.StatusOK, `{}`)
+		given("/job/fake/api/json", url.Values{"tree": {jobTree}}, http.StatusOK,
+			`{"builds":[{"number":2},{"number":1}]}`)
+		given("/job/fake/2/api/json", nil, http.StatusNotFound, `not found`)
+		given("/job/fake/1/api/json", nil, http.StatusOK, `{"number":1}`)
+
+		builds, err := jobClient.GetHistory("fake")
+		Expect(builds).To(HaveLen(1))
+		Expect(builds[0].Number).To(Equal(1))
+		var apiError *core.APIError
+		Expect(errors.As(err, &apiError)).To(BeTrue())
+		Expect(apiError.StatusCode).To(Equal(http.StatusNotFound))
+	})
+
+	It("no such job", func() {
+		given("/job/fake/api/json", allBuildsTree(""), http.StatusNotFound, `not found`)
+
+		_, err := jobClient.ListBuilds("fake", HistoryOptions{})
+		Expect(core.IsNotFound(err)).To(BeTrue())
+	})
+})
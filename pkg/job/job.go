@@ -2,6 +2,7 @@ package job
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -30,11 +31,28 @@ type Client struct {
 	Parent string
 }
 
+// NewClient creates a Client which shares the HTTP client with the JenkinsCore
+func NewClient(jenkins *core.JenkinsCore) *Client {
+	return &Client{JenkinsCore: jenkins.Clone()}
+}
+
 // Search find a set of jobs by name
 func (q *Client) Search(name, kind string, start, limit int) (items []JenkinsItem, err error) {
-	err = q.RequestWithData(http.MethodGet, fmt.Sprintf("/items/list?name=%s&type=%s&start=%d&limit=%d&parent=%s",
-		name, kind, start, limit, q.Parent),
-		nil, nil, 200, &items)
+	return q.SearchContext(context.Background(), name, kind, start, limit)
+}
+
+// SearchContext is the same as Search but accepts a context
+func (q *Client) SearchContext(ctx context.Context, name, kind string, start, limit int) (items []JenkinsItem, err error) {
+	request := core.NewRequestWithContext(ctx, "/items/list", &q.JenkinsCore).WithQuery(url.Values{
+		"name":   {name},
+		"type":   {kind},
+		"start":  {strconv.Itoa(start)},
+		"limit":  {strconv.Itoa(limit)},
+		"parent": {q.Parent},
+	})
+	if err = request.Do(); err == nil {
+		err = request.GetObject(&items)
+	}
 	return
 }
 
@@ -42,15 +60,23 @@ func (q *Client) Search(name, kind string, start, limit int) (items []JenkinsIte
 //
 // Deprecated: For clearer client of BlueOcean, please use BlueOceanClient#Search instead
 func (q *Client) SearchViaBlue(name string, start, limit int) (items []JenkinsItem, err error) {
+	return q.SearchViaBlueContext(context.Background(), name, start, limit)
+}
+
+// SearchViaBlueContext is the same as SearchViaBlue but accepts a context
+func (q *Client) SearchViaBlueContext(ctx context.Context, name string, start, limit int) (items []JenkinsItem, err error) {
 	boClient := BlueOceanClient{JenkinsCore: q.JenkinsCore, Organization: "jenkins"}
-	return boClient.Search(name, start, limit)
+	return boClient.SearchContext(ctx, name, start, limit)
 }
 
-// Build trigger a job
-func (q *Client) Build(jobName string) (err error) {
-	path := ParseJobPath(jobName)
-	_, err = q.RequestWithoutData(http.MethodPost, fmt.Sprintf("%s/build", path), nil, nil, 201)
-	return
+// Build trigger a job, then returns the queue item of the build
+func (q *Client) Build(jobName string) (ref QueueRef, err error) {
+	return q.BuildContext(context.Background(), jobName)
+}
+
+// BuildContext is the same as Build but accepts a context
+func (q *Client) BuildContext(ctx context.Context, jobName string) (ref QueueRef, err error) {
+	return q.BuildWithOptionsContext(ctx, jobName, TriggerOptions{})
 }
 
 // IdentityBuild is the build which carry the identity cause
@@ -68,25 +94,35 @@ type IdentityCause struct {
 
 // BuildAndReturn trigger a job then returns the build info
 func (q *Client) BuildAndReturn(jobName, cause string, timeout, delay int) (build IdentityBuild, err error) {
-	path := ParseJobPath(jobName)
+	return q.BuildAndReturnContext(context.Background(), jobName, cause, timeout, delay)
+}
 
-	api := fmt.Sprintf("%s/restFul/build?1=1", path)
+// BuildAndReturnContext is the same as BuildAndReturn but accepts a context
+func (q *Client) BuildAndReturnContext(ctx context.Context, jobName, cause string, timeout, delay int) (build IdentityBuild, err error) {
+	request := core.NewRequestWithContext(ctx, ParseJobPath(jobName)+"/restFul/build", &q.JenkinsCore).WithPostMethod()
 	if timeout >= 0 {
-		api += fmt.Sprintf("&timeout=%d", timeout)
+		request.AddQuery("timeout", strconv.Itoa(timeout))
 	}
 	if delay >= 0 {
-		api += fmt.Sprintf("&delay=%d", delay)
+		request.AddQuery("delay", strconv.Itoa(delay))
 	}
 	if cause != "" {
-		api += fmt.Sprintf("&identifyCause=%s", cause)
+		request.AddQuery("identifyCause", cause)
 	}
 
-	err = q.RequestWithData(http.MethodPost, api, nil, nil, 200, &build)
+	if err = request.Do(); err == nil {
+		err = request.GetObject(&build)
+	}
 	return
 }
 
 // GetBuild get build information of a job
 func (q *Client) GetBuild(jobName string, id int) (job *Build, err error) {
+	return q.GetBuildContext(context.Background(), jobName, id)
+}
+
+// GetBuildContext is the same as GetBuild but accepts a context
+func (q *Client) GetBuildContext(ctx context.Context, jobName string, id int) (job *Build, err error) {
 	path := ParseJobPath(jobName)
 	var api string
 	if id == -1 {
@@ -95,12 +131,18 @@ func (q *Client) GetBuild(jobName string, id int) (job *Build, err error) {
 		api = fmt.Sprintf("%s/%d/api/json", path, id)
 	}
 
-	err = q.RequestWithData("GET", api, nil, nil, 200, &job)
+	err = q.RequestWithDataContext(ctx, "GET", api, nil, nil, 200, &job)
 	return
 }
 
-// BuildWithParams build a job which has params
-func (q *Client) BuildWithParams(jobName string, parameters []ParameterDefinition) (err error) {
+// BuildWithParams build a job which has params, then returns the queue item of the build
+func (q *Client) BuildWithParams(jobName string, parameters []ParameterDefinition) (ref QueueRef, err error) {
+	return q.BuildWithParamsContext(context.Background(), jobName, parameters)
+}
+
+// BuildWithParamsContext is the same as BuildWithParams but accepts a context
+func (q *Client) BuildWithParamsContext(ctx context.Context, jobName string, parameters []ParameterDefinition) (
+	ref QueueRef, err error) {
 	path := ParseJobPath(jobName)
 	api := fmt.Sprintf("%s/build", path)
 
@@ -119,7 +161,7 @@ func (q *Client) BuildWithParams(jobName string, parameters []ParameterDefinitio
 			var file *os.File
 			file, err = os.Open(parameter.Filepath)
 			if err != nil {
-				return err
+				return
 			}
 			defer func(file *os.File) {
 				// ignore error
@@ -129,7 +171,7 @@ func (q *Client) BuildWithParams(jobName string, parameters []ParameterDefinitio
 			var fWriter io.Writer
 			fWriter, err = writer.CreateFormFile(parameter.Filepath, filepath.Base(parameter.Filepath))
 			if err != nil {
-				return err
+				return
 			}
 			_, err = io.Copy(fWriter, file)
 		} else {
@@ -156,38 +198,53 @@ func (q *Client) BuildWithParams(jobName string, parameters []ParameterDefinitio
 			return
 		}
 
-		_, err = q.RequestWithoutData(http.MethodPost, api,
-			map[string]string{"Content-Type": writer.FormDataContentType()}, body, 201)
+		ref, err = q.trigger(ctx, api, nil,
+			map[string]string{"Content-Type": writer.FormDataContentType()}, body)
 	} else {
 		formData := url.Values{"json": {fmt.Sprintf("{\"parameter\": %s}", string(paramJSON))}}
 		payload := strings.NewReader(formData.Encode())
 
-		_, err = q.RequestWithoutData(http.MethodPost, api,
-			map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, payload, 201)
+		ref, err = q.trigger(ctx, api, nil,
+			map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, payload)
 	}
 	return
 }
 
 // DisableJob disable a job
 func (q *Client) DisableJob(jobName string) (err error) {
+	return q.DisableJobContext(context.Background(), jobName)
+}
+
+// DisableJobContext is the same as DisableJob but accepts a context
+func (q *Client) DisableJobContext(ctx context.Context, jobName string) (err error) {
 	path := ParseJobPath(jobName)
 	api := fmt.Sprintf("%s/disable", path)
 
-	_, err = q.RequestWithoutData(http.MethodPost, api, nil, nil, 200)
+	_, err = q.RequestWithoutDataContext(ctx, http.MethodPost, api, nil, nil, 200)
 	return
 }
 
 // EnableJob disable a job
 func (q *Client) EnableJob(jobName string) (err error) {
+	return q.EnableJobContext(context.Background(), jobName)
+}
+
+// EnableJobContext is the same as EnableJob but accepts a context
+func (q *Client) EnableJobContext(ctx context.Context, jobName string) (err error) {
 	path := ParseJobPath(jobName)
 	api := fmt.Sprintf("%s/enable", path)
 
-	_, err = q.RequestWithoutData(http.MethodPost, api, nil, nil, 200)
+	_, err = q.RequestWithoutDataContext(ctx, http.MethodPost, api, nil, nil, 200)
 	return
 }
 
 // StopJob stops a job build
 func (q *Client) StopJob(jobName string, num int) (err error) {
+	return q.StopJobContext(context.Background(), jobName, num)
+}
+
+// StopJobContext is the same as StopJob but accepts a context
+func (q *Client) StopJobContext(ctx context.Context, jobName string, num int) (err error) {
 	path := ParseJobPath(jobName)
 
 	var api string
@@ -197,21 +254,29 @@ func (q *Client) StopJob(jobName string, num int) (err error) {
 		api = fmt.Sprintf("%s/%d/stop", path, num)
 	}
 
-	_, err = q.RequestWithoutData(http.MethodPost, api, nil, nil, 200)
+	_, err = q.RequestWithoutDataContext(ctx, http.MethodPost, api, nil, nil, 200)
 	return
 }
 
-// GetJob returns the job info
+// GetJob returns the job info, only the fields of Job are fetched
 func (q *Client) GetJob(name string) (job *Job, err error) {
-	path := ParseJobPath(name)
-	api := fmt.Sprintf("%s/api/json", path)
+	return q.GetJobContext(context.Background(), name)
+}
 
-	err = q.RequestWithData(http.MethodGet, api, nil, nil, 200, &job)
-	return
+// GetJobContext is the same as GetJob but accepts a context
+func (q *Client) GetJobContext(ctx context.Context, name string) (job *Job, err error) {
+	// only the number and URL of the builds like the default API, other fields make Jenkins load all the build records
+	tree := core.TreeFor[Job]().Set("builds", core.NewTree("number", "url"))
+	return core.GetAPI[*Job](ctx, &q.JenkinsCore, ParseJobPath(name), core.APIOptions{Tree: tree})
 }
 
 // AddParameters add parameters to a SimplePipeline
 func (q *Client) AddParameters(name, parameters string) (err error) {
+	return q.AddParametersContext(context.Background(), name, parameters)
+}
+
+// AddParametersContext is the same as AddParameters but accepts a context
+func (q *Client) AddParametersContext(ctx context.Context, name, parameters string) (err error) {
 	path := ParseJobPath(name)
 	api := fmt.Sprintf("%s/restFul/addParameter", path)
 
@@ -219,129 +284,101 @@ func (q *Client) AddParameters(name, parameters string) (err error) {
 		"params": {parameters},
 	}
 	payload := strings.NewReader(formData.Encode())
-	_, err = q.RequestWithoutData(http.MethodPost, api, map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, payload, 200)
+	_, err = q.RequestWithoutDataContext(ctx, http.MethodPost, api, map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, payload, 200)
 	return
 }
 
 // RemoveParameters add parameters to a SimplePipeline
 func (q *Client) RemoveParameters(name, parameters string) (err error) {
-	path := ParseJobPath(name)
-	api := fmt.Sprintf("%s/restFul/removeParameter?params=%s", path, parameters)
+	return q.RemoveParametersContext(context.Background(), name, parameters)
+}
 
-	_, err = q.RequestWithoutData(http.MethodPost, api, nil, nil, 200)
+// RemoveParametersContext is the same as RemoveParameters but accepts a context
+func (q *Client) RemoveParametersContext(ctx context.Context, name, parameters string) (err error) {
+	err = core.NewRequestWithContext(ctx, ParseJobPath(name)+"/restFul/removeParameter", &q.JenkinsCore).
+		WithPostMethod().AddQuery("params", parameters).Do()
 	return
 }
 
 // GetJobTypeCategories returns all categories of jobs
 func (q *Client) GetJobTypeCategories() (jobCategories []Category, err error) {
-	var (
-		statusCode int
-		data       []byte
-	)
-
-	if statusCode, data, err = q.Request("GET", "/view/all/itemCategories?depth=3", nil, nil); err == nil {
-		if statusCode == 200 {
-			type innerJobCategories struct {
-				Categories []Category
-			}
-			result := &innerJobCategories{}
-			err = json.Unmarshal(data, result)
-			jobCategories = result.Categories
-		} else {
-			err = fmt.Errorf("unexpected status code: %d", statusCode)
-		}
+	return q.GetJobTypeCategoriesContext(context.Background())
+}
+
+// GetJobTypeCategoriesContext is the same as GetJobTypeCategories but accepts a context
+func (q *Client) GetJobTypeCategoriesContext(ctx context.Context) (jobCategories []Category, err error) {
+	type innerJobCategories struct {
+		Categories []Category
+	}
+	result := &innerJobCategories{}
+	if err = q.RequestWithDataContext(ctx, http.MethodGet, "/view/all/itemCategories?depth=3", nil, nil, 200, result); err == nil {
+		jobCategories = result.Categories
 	}
 	return
 }
 
 // GetPipeline return the pipeline object
 func (q *Client) GetPipeline(name string) (pipeline *SimplePipeline, err error) {
+	return q.GetPipelineContext(context.Background(), name)
+}
+
+// GetPipelineContext is the same as GetPipeline but accepts a context
+func (q *Client) GetPipelineContext(ctx context.Context, name string) (pipeline *SimplePipeline, err error) {
 	path := ParseJobPath(name)
 	api := fmt.Sprintf("%s/restFul", path)
-	err = q.RequestWithData("GET", api, nil, nil, 200, &pipeline)
+	err = q.RequestWithDataContext(ctx, "GET", api, nil, nil, 200, &pipeline)
 	return
 }
 
 // UpdatePipeline updates the pipeline script
 func (q *Client) UpdatePipeline(name, script string) (err error) {
+	return q.UpdatePipelineContext(context.Background(), name, script)
+}
+
+// UpdatePipelineContext is the same as UpdatePipeline but accepts a context
+func (q *Client) UpdatePipelineContext(ctx context.Context, name, script string) (err error) {
 	formData := url.Values{}
 	formData.Add("script", script)
 
 	path := ParseJobPath(name)
 	api := fmt.Sprintf("%s/restFul/update?%s", path, formData.Encode())
 
-	_, err = q.RequestWithoutData(http.MethodPost, api, nil, nil, 200)
+	_, err = q.RequestWithoutDataContext(ctx, http.MethodPost, api, nil, nil, 200)
 	return
 }
 
-// GetHistory returns the build history of a job
+// GetHistory returns the build history of a job, see also ListBuilds
 func (q *Client) GetHistory(name string) (builds []*Build, err error) {
-	var job *Job
-	if job, err = q.GetJob(name); err == nil {
-		buildList := job.Builds // only contains basic info
+	return q.GetHistoryContext(context.Background(), name)
+}
 
-		var build *Build
-		for _, buildItem := range buildList {
-			build, err = q.GetBuild(name, buildItem.Number)
-			if err != nil {
-				break
-			}
-			builds = append(builds, build)
-		}
-	}
-	return
+// GetHistoryContext is the same as GetHistory but accepts a context.
+// The latest 100 builds are fetched in one request, see ListBuildsContext for the details.
+func (q *Client) GetHistoryContext(ctx context.Context, name string) (builds []*Build, err error) {
+	return q.ListBuildsContext(ctx, name, HistoryOptions{Limit: historyLimit})
 }
 
 // DeleteHistory returns the build history of a job
 func (q *Client) DeleteHistory(jobName string, num int) (err error) {
+	return q.DeleteHistoryContext(context.Background(), jobName, num)
+}
+
+// DeleteHistoryContext is the same as DeleteHistory but accepts a context
+func (q *Client) DeleteHistoryContext(ctx context.Context, jobName string, num int) (err error) {
 	path := ParseJobPath(jobName)
 	api := fmt.Sprintf("%s/%d/doDelete", path, num)
-	_, err = q.RequestWithoutData(http.MethodPost, api, nil, nil, 200)
+	_, err = q.RequestWithoutDataContext(ctx, http.MethodPost, api, nil, nil, 200)
 	return
 }
 
-// Log get the log of a job
+// Log get a chunk of the log of a job from the start, see also FollowLog
 func (q *Client) Log(jobName string, history int, start int64) (jobLog Log, err error) {
-	path := ParseJobPath(jobName)
-	var api string
-	if history == -1 {
-		api = fmt.Sprintf("%s%s/lastBuild/logText/progressiveText?start=%d", q.URL, path, start)
-	} else {
-		api = fmt.Sprintf("%s%s/%d/logText/progressiveText?start=%d", q.URL, path, history, start)
-	}
-	var (
-		req      *http.Request
-		response *http.Response
-	)
-
-	req, err = http.NewRequest("GET", api, nil)
-	if err == nil {
-		err = q.AuthHandle(req)
-	}
-	if err != nil {
-		return
-	}
-
-	client := q.GetClient()
-	jobLog = Log{
-		HasMore:   false,
-		Text:      "",
-		NextStart: int64(0),
-	}
-
-	if response, err = client.Do(req); err == nil {
-		code := response.StatusCode
-		var data []byte
-		data, err = io.ReadAll(response.Body)
-		if code == 200 {
-			jobLog.Text = string(data)
+	return q.LogContext(context.Background(), jobName, history, start)
+}
 
-			if response.Header != nil {
-				jobLog.HasMore = strings.ToLower(response.Header.Get("X-More-Data")) == "true"
-				jobLog.NextStart, _ = strconv.ParseInt(response.Header.Get("X-Text-Size"), 10, 64)
-			}
-		}
-	}
+// LogContext is the same as Log but accepts a context
+func (q *Client) LogContext(ctx context.Context, jobName string, history int, start int64) (jobLog Log, err error) {
+	jobLog, _, err = q.progressiveLog(ctx, jobName, history, start, false, "")
 	return
 }
 
@@ -354,11 +391,21 @@ type CreateJobPayload struct {
 
 // Create can create a job
 func (q *Client) Create(jobPayload CreateJobPayload) (err error) {
-	return q.CreateJobInFolder(jobPayload, "")
+	return q.CreateContext(context.Background(), jobPayload)
+}
+
+// CreateContext is the same as Create but accepts a context
+func (q *Client) CreateContext(ctx context.Context, jobPayload CreateJobPayload) (err error) {
+	return q.CreateJobInFolderContext(ctx, jobPayload, "")
 }
 
 // CreateJobInFolder creates a job in a specific folder and create folder first if the folder does not exist
 func (q *Client) CreateJobInFolder(jobPayload CreateJobPayload, path string) (err error) {
+	return q.CreateJobInFolderContext(context.Background(), jobPayload, path)
+}
+
+// CreateJobInFolderContext is the same as CreateJobInFolder but accepts a context
+func (q *Client) CreateJobInFolderContext(ctx context.Context, jobPayload CreateJobPayload, path string) (err error) {
 	// create a job in path
 	playLoadData, _ := json.Marshal(jobPayload)
 	formData := url.Values{
@@ -371,7 +418,7 @@ func (q *Client) CreateJobInFolder(jobPayload CreateJobPayload, path string) (er
 	path = ParseJobPath(path)
 	api := fmt.Sprintf("/view/all%s/createItem", path)
 	var code int
-	code, err = q.RequestWithoutData(http.MethodPost, api,
+	code, err = q.RequestWithoutDataContext(ctx, http.MethodPost, api,
 		map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, payload, 200)
 	if code == 302 {
 		err = nil
@@ -381,28 +428,28 @@ func (q *Client) CreateJobInFolder(jobPayload CreateJobPayload, path string) (er
 
 // Delete will delete a job by name
 func (q *Client) Delete(jobName string) (err error) {
-	var (
-		statusCode int
-	)
+	return q.DeleteContext(context.Background(), jobName)
+}
 
+// DeleteContext is the same as Delete but accepts a context
+func (q *Client) DeleteContext(ctx context.Context, jobName string) (err error) {
 	jobName = ParseJobPath(jobName)
 	api := fmt.Sprintf("%s/doDelete", jobName)
-	header := map[string]string{
-		"Content-Type": "application/x-www-form-urlencoded",
-	}
 
-	if statusCode, _, err = q.Request(http.MethodPost, api, header, nil); err == nil {
-		if statusCode != 200 && statusCode != 302 {
-			err = fmt.Errorf("unexpected status code: %d", statusCode)
-		}
-	}
+	request := core.NewRequestWithContext(ctx, api, &q.JenkinsCore)
+	err = request.AsPostFormRequest().AcceptStatusCode(302).Do()
 	return
 }
 
 // GetJobInputActions returns the all pending actions
 func (q *Client) GetJobInputActions(jobName string, buildID int) (actions []InputItem, err error) {
+	return q.GetJobInputActionsContext(context.Background(), jobName, buildID)
+}
+
+// GetJobInputActionsContext is the same as GetJobInputActions but accepts a context
+func (q *Client) GetJobInputActionsContext(ctx context.Context, jobName string, buildID int) (actions []InputItem, err error) {
 	path := ParseJobPath(jobName)
-	err = q.RequestWithData("GET", fmt.Sprintf("%s/%d/wfapi/pendingInputActions", path, buildID), nil, nil, 200, &actions)
+	err = q.RequestWithDataContext(ctx, "GET", fmt.Sprintf("%s/%d/wfapi/pendingInputActions", path, buildID), nil, nil, 200, &actions)
 	return
 }
 
@@ -413,12 +460,17 @@ type JenkinsInputParametersRequest struct {
 
 // JobInputSubmit submit the pending input request
 func (q *Client) JobInputSubmit(jobName, inputID string, buildID int, abort bool, params map[string]string) (err error) {
+	return q.JobInputSubmitContext(context.Background(), jobName, inputID, buildID, abort, params)
+}
+
+// JobInputSubmitContext is the same as JobInputSubmit but accepts a context
+func (q *Client) JobInputSubmitContext(ctx context.Context, jobName, inputID string, buildID int, abort bool, params map[string]string) (err error) {
 	jobPath := ParseJobPath(jobName)
 	var api string
 	if abort {
-		api = fmt.Sprintf("%s/%d/input/%s/abort", jobPath, buildID, inputID)
+		api = fmt.Sprintf("%s/%d/input/%s/abort", jobPath, buildID, core.EscapePathSegment(inputID))
 	} else {
-		api = fmt.Sprintf("%s/%d/input/%s/proceed", jobPath, buildID, inputID)
+		api = fmt.Sprintf("%s/%d/input/%s/proceed", jobPath, buildID, core.EscapePathSegment(inputID))
 	}
 
 	request := JenkinsInputParametersRequest{
@@ -434,13 +486,15 @@ func (q *Client) JobInputSubmit(jobName, inputID string, buildID int, abort bool
 
 	paramData, _ := json.Marshal(request)
 
-	api = fmt.Sprintf("%s?json=%s", api, string(paramData))
-	_, err = q.RequestWithoutData(http.MethodPost, api, nil, nil, 200)
-
+	err = core.NewRequestWithContext(ctx, api, &q.JenkinsCore).WithPostMethod().
+		AddQuery("json", string(paramData)).Do()
 	return
 }
 
-// ParseJobPath leads with slash
+// ParseJobPath returns the URL path of a job which leads with slash.
+// The job name could be a full name separated by slashes, e.g. "folder/job", the item names may contain whitespaces.
+// A name without slash is split by whitespaces as the legacy form, e.g. "folder job".
+// A URL path which leads with "/job/" or "job/" is returned as it is.
 func ParseJobPath(jobName string) (path string) {
 	path = jobName
 	if jobName == "" || strings.HasPrefix(jobName, "/job/") ||
@@ -448,12 +502,10 @@ func ParseJobPath(jobName string) (path string) {
 		return
 	}
 
-	jobItems := strings.Split(jobName, " ")
-	path = ""
-	for _, item := range jobItems {
-		path = fmt.Sprintf("%s/job/%s", path, item)
+	if strings.Contains(jobName, "/") {
+		return core.ParseFullName(jobName).String()
 	}
-	return
+	return core.NewItemPath(strings.Split(jobName, " ")...).String()
 }
 
 // parsePipelinePath parses multiple pipelines and leads with slash.
@@ -462,7 +514,11 @@ func parsePipelinePath(pipelines []string) string {
 	if len(pipelines) == 0 {
 		return ""
 	}
-	return "pipelines/" + strings.Join(pipelines, "/pipelines/")
+	escaped := make([]string, len(pipelines))
+	for i, pipeline := range pipelines {
+		escaped[i] = core.EscapePathSegment(pipeline)
+	}
+	return "pipelines/" + strings.Join(escaped, "/pipelines/")
 }
 
 // Log holds the log text
@@ -562,7 +618,7 @@ type Build struct {
 	FullDisplayName   string
 	ID                string
 	KeepLog           bool
-	QueueID           int
+	QueueID           int `tree:"queueId"`
 	Result            string
 	Timestamp         int64
 	PreviousBuild     SimpleJobBuild
@@ -2,10 +2,13 @@ package job
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"reflect"
 	"testing"
 
@@ -90,7 +93,7 @@ var _ = Describe("job test", func() {
 			roundTripper.EXPECT().
 				RoundTrip(core.NewRequestMatcher(requestCrumb)).Return(responseCrumb, nil)
 
-			err := jobClient.Build(jobName)
+			_, err := jobClient.Build(jobName)
 			Expect(err).To(BeNil())
 		})
 
@@ -119,11 +122,24 @@ var _ = Describe("job test", func() {
 			roundTripper.EXPECT().
 				RoundTrip(core.NewRequestMatcher(requestCrumb)).Return(responseCrumb, nil)
 
-			err := jobClient.Build(jobName)
+			_, err := jobClient.Build(jobName)
 			Expect(err).To(HaveOccurred())
 		})
 	})
 
+	Context("BuildAndReturn", func() {
+		It("escape the cause in the query", func() {
+			request, _ := http.NewRequest(http.MethodPost, jobClient.URL+
+				"/job/fakeJob/restFul/build?delay=0&identifyCause=a%26timeout%3D1+b&timeout=30", nil)
+			core.PrepareCommonPost(request, `{"build":{"number":1},"cause":{"uuid":"a&timeout=1 b"}}`,
+				roundTripper, "", "", jobClient.URL)
+
+			build, err := jobClient.BuildAndReturn("fakeJob", "a&timeout=1 b", 30, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(build.Cause.UUID).To(Equal("a&timeout=1 b"))
+		})
+	})
+
 	Context("GetBuild", func() {
 		It("basic case with the last build", func() {
 			jobName := "fake"
@@ -163,7 +179,7 @@ var _ = Describe("job test", func() {
 
 			PrepareForBuildWithNoParams(roundTripper, jobClient.URL, jobName, "", "")
 
-			err := jobClient.BuildWithParams(jobName, []ParameterDefinition{})
+			_, err := jobClient.BuildWithParams(jobName, []ParameterDefinition{})
 			Expect(err).To(BeNil())
 		})
 
@@ -172,7 +188,7 @@ var _ = Describe("job test", func() {
 
 			PrepareForBuildWithParams(roundTripper, jobClient.URL, jobName, "", "")
 
-			err := jobClient.BuildWithParams(jobName, []ParameterDefinition{{
+			_, err := jobClient.BuildWithParams(jobName, []ParameterDefinition{{
 				Name:  "name",
 				Value: "value",
 				Type:  StringParameterDefinition,
@@ -264,6 +280,26 @@ var _ = Describe("job test", func() {
 			Expect(result).NotTo(BeNil())
 			Expect(result.Name).To(Equal(jobName))
 		})
+
+		It("only fetch the fields of Job and the number of the builds", func() {
+			request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/job/fake/api/json?%s", jobClient.URL, url.Values{
+				"tree": {"_class,builds[number,url],color,concurrentBuild,name,nextBuildNumber,url,buildable," +
+					"property[parameterDefinitions[description,name,type,value,file," +
+					"defaultParameterValue[name,value,jobName,number],choices,projectName,filter]]"},
+			}.Encode()), nil)
+			response := &http.Response{
+				StatusCode: 200,
+				Request:    request,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"name":"fake","builds":[{"number":1,"url":"http://localhost/job/fake/1/"}]}`)),
+			}
+			roundTripper.EXPECT().
+				RoundTrip(core.NewRequestMatcher(request).WithQuery()).Return(response, nil)
+
+			result, err := jobClient.GetJob("fake")
+			Expect(err).To(BeNil())
+			Expect(result.Builds).To(HaveLen(1))
+			Expect(result.Builds[0].URL).To(Equal("http://localhost/job/fake/1/"))
+		})
 	})
 
 	Context("GetJobTypeCategories", func() {
@@ -381,15 +417,32 @@ var _ = Describe("job test", func() {
 		It("simple case, should success", func() {
 			jobName := "fakeJob"
 
-			PrepareForGetJob(roundTripper, jobClient.URL, jobName, "", "")
-			PrepareForGetBuild(roundTripper, jobClient.URL, jobName, 1, "", "")
-			PrepareForGetBuild(roundTripper, jobClient.URL, jobName, 2, "", "")
+			tree := "allBuilds[" + core.TreeFor[Build]().String() + "]{0,100}"
+			request, _ := http.NewRequest(http.MethodGet, jobClient.URL+"/job/fakeJob/api/json?"+
+				url.Values{"tree": {tree}}.Encode(), nil)
+			roundTripper.EXPECT().RoundTrip(core.NewRequestMatcher(request).WithQuery()).Return(&http.Response{
+				StatusCode: http.StatusOK,
+				Request:    request,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"allBuilds":[{"number":2},{"number":1}]}`)),
+			}, nil).Times(1)
 
 			builds, err := jobClient.GetHistory(jobName)
 			Expect(err).To(BeNil())
 			Expect(builds).NotTo(BeNil())
 			Expect(len(builds)).To(Equal(2))
 		})
+
+		It("with a canceled context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				return nil, req.Context().Err()
+			}).AnyTimes()
+
+			builds, err := jobClient.GetHistoryContext(ctx, "fakeJob")
+			Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+			Expect(builds).To(BeEmpty())
+		})
 	})
 
 	Context("Log", func() {
@@ -469,6 +522,16 @@ var _ = Describe("test function ParseJobPath", func() {
 		})
 	})
 
+	Context("full name separated by slashes", func() {
+		BeforeEach(func() {
+			jobName = "my folder/my job"
+		})
+
+		It("should keep the whitespaces in the names", func() {
+			Expect(path).To(Equal("/job/my%20folder/job/my%20job"))
+		})
+	})
+
 	Context("job name with URL path", func() {
 		BeforeEach(func() {
 			jobName = "/job/abc/job/def"
@@ -478,6 +541,16 @@ var _ = Describe("test function ParseJobPath", func() {
 			Expect(path).To(Equal(jobName))
 		})
 	})
+
+	Context("job name with special characters", func() {
+		BeforeEach(func() {
+			jobName = "a#b c%d?e&f"
+		})
+
+		It("should be escaped", func() {
+			Expect(path).To(Equal("/job/a%23b/job/c%25d%3Fe&f"))
+		})
+	})
 })
 
 func TestParsePipelinePath(t *testing.T) {
@@ -38,7 +38,8 @@ func PrepareForGetJobInputActions(roundTripper *mhttp.MockRoundTripper, rootURL,
 // PrepareForSubmitInput only for test
 func PrepareForSubmitInput(roundTripper *mhttp.MockRoundTripper, rootURL, jobPath, user, password string) (
 	request *http.Request, response *http.Response) {
-	request, _ = http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s/%d/input/%s/abort?json={\"parameter\":[]}", rootURL, jobPath, 1, "Eff7d5dba32b4da32d9a67a519434d3f"), nil)
+	request, _ = http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s/%d/input/%s/abort?%s", rootURL, jobPath, 1, "Eff7d5dba32b4da32d9a67a519434d3f",
+		url.Values{"json": {`{"parameter":[]}`}}.Encode()), nil)
 	core.PrepareCommonPost(request, "", roundTripper, user, password, rootURL)
 	return
 }
@@ -46,7 +47,8 @@ func PrepareForSubmitInput(roundTripper *mhttp.MockRoundTripper, rootURL, jobPat
 // PrepareForSubmitProcessInput only for test
 func PrepareForSubmitProcessInput(roundTripper *mhttp.MockRoundTripper, rootURL, jobPath, user, password string) (
 	request *http.Request, response *http.Response) {
-	request, _ = http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s/%d/input/%s/proceed?json={\"parameter\":[]}", rootURL, jobPath, 1, "Eff7d5dba32b4da32d9a67a519434d3f"), nil)
+	request, _ = http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s/%d/input/%s/proceed?%s", rootURL, jobPath, 1, "Eff7d5dba32b4da32d9a67a519434d3f",
+		url.Values{"json": {`{"parameter":[]}`}}.Encode()), nil)
 	core.PrepareCommonPost(request, "", roundTripper, user, password, rootURL)
 	return
 }
@@ -190,15 +192,15 @@ func PrepareForJobLog(roundTripper *mhttp.MockRoundTripper, rootURL, jobName str
 
 // PrepareOneItem only for test
 func PrepareOneItem(roundTripper *mhttp.MockRoundTripper, rootURL, name, kind, user, token string) {
-	request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/items/list?name=%s&type=%s&start=%d&limit=%d&parent=",
-		rootURL, name, kind, 0, 50), nil)
+	query := url.Values{"name": {name}, "type": {kind}, "start": {"0"}, "limit": {"50"}, "parent": {""}}
+	request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/items/list?%s", rootURL, query.Encode()), nil)
 	response := &http.Response{
 		StatusCode: 200,
 		Request:    request,
 		Body:       ioutil.NopCloser(bytes.NewBufferString(`[{"name":"fake","displayName":"fake","description":null,"type":"WorkflowJob","shortURL":"job/fake/","url":"job/fake/"}]`)),
 	}
 	roundTripper.EXPECT().
-		RoundTrip(core.NewRequestMatcher(request)).Return(response, nil)
+		RoundTrip(core.NewRequestMatcher(request).WithQuery()).Return(response, nil)
 	if user != "" && token != "" {
 		request.SetBasicAuth(user, token)
 	}
@@ -206,15 +208,15 @@ func PrepareOneItem(roundTripper *mhttp.MockRoundTripper, rootURL, name, kind, u
 
 // PrepareEmptyItems only for test
 func PrepareEmptyItems(roundTripper *mhttp.MockRoundTripper, rootURL, name, kind, user, token string) {
-	request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/items/list?name=%s&type=%s&start=%d&limit=%d&parent=",
-		rootURL, name, kind, 0, 50), nil)
+	query := url.Values{"name": {name}, "type": {kind}, "start": {"0"}, "limit": {"50"}, "parent": {""}}
+	request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/items/list?%s", rootURL, query.Encode()), nil)
 	response := &http.Response{
 		StatusCode: 200,
 		Request:    request,
 		Body:       ioutil.NopCloser(bytes.NewBufferString(`[]`)),
 	}
 	roundTripper.EXPECT().
-		RoundTrip(core.NewRequestMatcher(request)).Return(response, nil)
+		RoundTrip(core.NewRequestMatcher(request).WithQuery()).Return(response, nil)
 	if user != "" && token != "" {
 		request.SetBasicAuth(user, token)
 	}
@@ -0,0 +1,184 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/verystar/jenkins-client/pkg/core"
+	"golang.org/x/net/html"
+)
+
+const (
+	// DefaultLogMinInterval is the interval of polling the log after getting some new text
+	DefaultLogMinInterval = 500 * time.Millisecond
+	// DefaultLogMaxInterval is the max interval of polling the log when there is no new text
+	DefaultLogMaxInterval = 5 * time.Second
+)
+
+// LogOptions is the options of following the console log of a build
+type LogOptions struct {
+	// Start is the byte offset to resume from, it is the NextStart of the Log
+	Start int64
+	// HTML fetches the annotated log via progressiveHtml, then strips the HTML tags
+	HTML bool
+	// MinInterval is DefaultLogMinInterval if it is not positive
+	MinInterval time.Duration
+	// MaxInterval is DefaultLogMaxInterval if it is not positive
+	MaxInterval time.Duration
+}
+
+// FollowLog writes the console log of a build until the build finishes, then returns the offset of the next start.
+// The poll interval grows from MinInterval to MaxInterval while there is no new text.
+// Please use a build number rather than -1, or a newer build might be followed once it started.
+func (q *Client) FollowLog(ctx context.Context, jobName string, build int, w io.Writer, options LogOptions) (
+	next int64, err error) {
+	var annotator string
+	return followLog(ctx, w, options, func(ctx context.Context, start int64) (jobLog Log, err error) {
+		jobLog, annotator, err = q.progressiveLog(ctx, jobName, build, start, options.HTML, annotator)
+		return
+	})
+}
+
+// LogReader returns a reader of the console log of a build, it follows the log as FollowLog does.
+// The reader must be closed, the following stops once it is closed.
+func (q *Client) LogReader(ctx context.Context, jobName string, build int, options LogOptions) io.ReadCloser {
+	return newLogReader(ctx, func(ctx context.Context, w io.Writer) (err error) {
+		_, err = q.FollowLog(ctx, jobName, build, w, options)
+		return
+	})
+}
+
+// followLog fetches the log chunks from the start until there is no more data
+func followLog(ctx context.Context, w io.Writer, options LogOptions,
+	fetch func(ctx context.Context, start int64) (Log, error)) (next int64, err error) {
+	minInterval, maxInterval := options.MinInterval, options.MaxInterval
+	if minInterval <= 0 {
+		minInterval = DefaultLogMinInterval
+	}
+	if maxInterval <= 0 {
+		maxInterval = DefaultLogMaxInterval
+	}
+
+	next = options.Start
+	interval := minInterval
+	for {
+		var jobLog Log
+		if jobLog, err = fetch(ctx, next); err != nil {
+			return
+		}
+		next = jobLog.NextStart
+
+		if jobLog.Text != "" {
+			if _, err = io.WriteString(w, jobLog.Text); err != nil {
+				return
+			}
+			interval = minInterval
+		} else if interval *= 2; interval > maxInterval {
+			interval = maxInterval
+		}
+		if !jobLog.HasMore {
+			return
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			err = ctx.Err()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// newLogReader runs the follow function in a goroutine, its output is the content of the reader
+func newLogReader(ctx context.Context, follow func(ctx context.Context, w io.Writer) error) io.ReadCloser {
+	ctx, cancel := context.WithCancel(ctx)
+	reader, writer := io.Pipe()
+	go func() {
+		_ = writer.CloseWithError(follow(ctx, writer))
+	}()
+	return &logReader{PipeReader: reader, cancel: cancel}
+}
+
+type logReader struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+// Close stops following the log
+func (r *logReader) Close() error {
+	r.cancel()
+	return r.PipeReader.Close()
+}
+
+// progressiveLog fetches a chunk of the log from the start, the annotator is the state of the annotated log
+func (q *Client) progressiveLog(ctx context.Context, jobName string, build int, start int64, annotated bool,
+	annotator string) (jobLog Log, nextAnnotator string, err error) {
+	api := ParseJobPath(jobName)
+	if build == -1 {
+		api += "/lastBuild"
+	} else {
+		api += fmt.Sprintf("/%d", build)
+	}
+	var headers map[string]string
+	if annotated {
+		api += fmt.Sprintf("/logText/progressiveHtml?start=%d", start)
+		if annotator != "" {
+			headers = map[string]string{"X-ConsoleAnnotator": annotator}
+		}
+	} else {
+		api += fmt.Sprintf("/logText/progressiveText?start=%d", start)
+	}
+
+	var response *http.Response
+	if response, err = q.RequestWithResponseContext(ctx, http.MethodGet, api, headers, nil); err == nil {
+		if jobLog, err = readLog(response, start); err == nil && annotated {
+			jobLog.Text = stripHTML(jobLog.Text)
+			nextAnnotator = response.Header.Get("X-ConsoleAnnotator")
+		}
+	}
+	return
+}
+
+// readLog reads a chunk of the progressive log from the response, then closes the response body
+func readLog(response *http.Response, start int64) (jobLog Log, err error) {
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	var data []byte
+	if data, err = io.ReadAll(response.Body); err != nil {
+		return
+	}
+	if response.StatusCode != http.StatusOK {
+		err = core.NewAPIError(response, data)
+		return
+	}
+
+	jobLog.Text = string(data)
+	jobLog.HasMore = strings.ToLower(response.Header.Get("X-More-Data")) == "true"
+	if jobLog.NextStart, err = strconv.ParseInt(response.Header.Get("X-Text-Size"), 10, 64); err != nil {
+		jobLog.NextStart, err = start+int64(len(data)), nil
+	}
+	return
+}
+
+// stripHTML returns the text of the annotated log
+func stripHTML(text string) string {
+	tokenizer := html.NewTokenizer(strings.NewReader(text))
+	builder := strings.Builder{}
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return builder.String()
+		case html.TextToken:
+			builder.Write(tokenizer.Text())
+		}
+	}
+}
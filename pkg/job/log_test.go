@@ -0,0 +1,124 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/core"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("follow log test", func() {
+	var (
+		ctrl         *gomock.Controller
+		jobClient    Client
+		roundTripper *mhttp.MockRoundTripper
+		options      LogOptions
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		jobClient = Client{}
+		jobClient.RoundTripper = roundTripper
+		jobClient.URL = "http://localhost"
+		options = LogOptions{MinInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	given := func(api string, header http.Header, code int, body string) *http.Request {
+		request, _ := http.NewRequest(http.MethodGet, jobClient.URL+api, nil)
+		roundTripper.EXPECT().RoundTrip(core.NewRequestMatcher(request).WithQuery()).Return(&http.Response{
+			StatusCode: code,
+			Request:    request,
+			Header:     header,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+		}, nil)
+		return request
+	}
+	chunk := func(more string, size string) http.Header {
+		return http.Header{"X-More-Data": {more}, "X-Text-Size": {size}}
+	}
+
+	It("follow the log until the build finishes", func() {
+		given("/job/fake/3/logText/progressiveText?start=0", chunk("true", "6"), http.StatusOK, "hello\n")
+		given("/job/fake/3/logText/progressiveText?start=6", chunk("true", "6"), http.StatusOK, "")
+		given("/job/fake/3/logText/progressiveText?start=6", chunk("false", "12"), http.StatusOK, "world\n")
+
+		buffer := &bytes.Buffer{}
+		next, err := jobClient.FollowLog(context.Background(), "fake", 3, buffer, options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(next).To(Equal(int64(12)))
+		Expect(buffer.String()).To(Equal("hello\nworld\n"))
+	})
+
+	It("resume from an offset", func() {
+		given("/job/fake/3/logText/progressiveText?start=6", chunk("false", "12"), http.StatusOK, "world\n")
+
+		options.Start = 6
+		buffer := &bytes.Buffer{}
+		_, err := jobClient.FollowLog(context.Background(), "fake", 3, buffer, options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buffer.String()).To(Equal("world\n"))
+	})
+
+	It("strip the annotations of the HTML log", func() {
+		header := chunk("true", "40")
+		header.Set("X-ConsoleAnnotator", "state")
+		given("/job/fake/3/logText/progressiveHtml?start=0", header, http.StatusOK,
+			`<span class="timestamp"><b>10:00</b> </span>Started by <a href="/user/admin">admin</a> &amp; more`+"\n")
+		request := given("/job/fake/3/logText/progressiveHtml?start=40", chunk("false", "40"), http.StatusOK, "")
+		request.Header.Set("X-ConsoleAnnotator", "state")
+
+		options.HTML = true
+		buffer := &bytes.Buffer{}
+		_, err := jobClient.FollowLog(context.Background(), "fake", 3, buffer, options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buffer.String()).To(Equal("10:00 Started by admin & more\n"))
+	})
+
+	It("with an error status code", func() {
+		given("/job/fake/3/logText/progressiveText?start=0", nil, http.StatusNotFound, "not found")
+
+		_, err := jobClient.Log("fake", 3, 0)
+		Expect(core.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("read the log", func() {
+		given("/job/fake/3/logText/progressiveText?start=0", chunk("true", "6"), http.StatusOK, "hello\n")
+		given("/job/fake/3/logText/progressiveText?start=6", chunk("false", "12"), http.StatusOK, "world\n")
+
+		reader := jobClient.LogReader(context.Background(), "fake", 3, options)
+		data, err := io.ReadAll(reader)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("hello\nworld\n"))
+		Expect(reader.Close()).To(Succeed())
+	})
+
+	It("stop reading the log", func() {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(request *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Request:    request,
+				Header:     chunk("true", "6"),
+				Body:       io.NopCloser(strings.NewReader("")),
+			}, nil
+		}).AnyTimes()
+
+		reader := jobClient.LogReader(context.Background(), "fake", 3, options)
+		Expect(reader.Close()).To(Succeed())
+		_, err := reader.Read(make([]byte, 1))
+		Expect(errors.Is(err, io.ErrClosedPipe)).To(BeTrue())
+	})
+})
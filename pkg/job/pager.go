@@ -0,0 +1,82 @@
+package job
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/verystar/jenkins-client/pkg/core"
+)
+
+// blueOceanMaxPageSize is the max page size of the BlueOcean API, a larger limit is capped silently
+const blueOceanMaxPageSize = 100
+
+// SearchPager returns a Pager of the jobs which match the name and the type
+func (q *Client) SearchPager(name, kind string, pageSize int) *core.Pager[JenkinsItem] {
+	return core.NewPager(pageSize, func(ctx context.Context, start, limit int) ([]JenkinsItem, error) {
+		return q.SearchContext(ctx, name, kind, start, limit)
+	})
+}
+
+// SearchPager returns a Pager of the Pipelines which match the name
+func (c *BlueOceanClient) SearchPager(name string, pageSize int) *core.Pager[JenkinsItem] {
+	return core.NewPager(blueOceanPageSize(pageSize), func(ctx context.Context, start, limit int) ([]JenkinsItem, error) {
+		return c.SearchContext(ctx, name, start, limit)
+	})
+}
+
+// BranchesPager returns a Pager of the branches of a Pipeline, the Start and Limit of the option are ignored
+func (c *BlueOceanClient) BranchesPager(option GetBranchesOption, pageSize int) *core.Pager[PipelineBranch] {
+	return core.NewPager(blueOceanPageSize(pageSize), func(ctx context.Context, start, limit int) ([]PipelineBranch, error) {
+		option.Start, option.Limit = start, limit
+		return c.GetBranchesContext(ctx, option)
+	})
+}
+
+// PipelineRunsPager returns a Pager of the runs of a Pipeline which in the possible nest folders
+func (c *BlueOceanClient) PipelineRunsPager(pageSize int, pipeline string, folders ...string) *core.Pager[PipelineRun] {
+	api := c.getGetPipelineAPI(pipeline, folders...) + "/runs/"
+	return core.NewPager(blueOceanPageSize(pageSize), func(ctx context.Context, start, limit int) ([]PipelineRun, error) {
+		return getBlueOceanPage[PipelineRun](ctx, c, api, start, limit)
+	})
+}
+
+// PipelinesPager returns a Pager of the Pipelines in the possible nest folders
+func (c *BlueOceanClient) PipelinesPager(pageSize int, folders ...string) *core.Pager[Pipeline] {
+	api := c.getPipelineAPI(folders...)
+	return core.NewPager(blueOceanPageSize(pageSize), func(ctx context.Context, start, limit int) ([]Pipeline, error) {
+		return getBlueOceanPage[Pipeline](ctx, c, api, start, limit)
+	})
+}
+
+// ArtifactsPager returns a Pager of the artifacts of a PipelineRun
+func (c *BlueOceanClient) ArtifactsPager(option GetBuildOption, pageSize int) *core.Pager[BlueArtifact] {
+	api := c.getGetBuildAPI(option) + "artifacts/"
+	return core.NewPager(blueOceanPageSize(pageSize), func(ctx context.Context, start, limit int) ([]BlueArtifact, error) {
+		return getBlueOceanPage[BlueArtifact](ctx, c, api, start, limit)
+	})
+}
+
+// FavoritesPager returns a Pager of the favorites of a user
+func (c *BlueOceanClient) FavoritesPager(userID string, pageSize int) *core.Pager[BlueFavorite] {
+	api := getFavoritesAPI(userID)
+	return core.NewPager(blueOceanPageSize(pageSize), func(ctx context.Context, start, limit int) ([]BlueFavorite, error) {
+		return getBlueOceanPage[BlueFavorite](ctx, c, api, start, limit)
+	})
+}
+
+func getBlueOceanPage[T any](ctx context.Context, c *BlueOceanClient, api string, start, limit int) (items []T, err error) {
+	request := core.NewRequestWithContext(ctx, api, &c.JenkinsCore).
+		AddQuery("start", strconv.Itoa(start)).
+		AddQuery("limit", strconv.Itoa(limit))
+	if err = request.Do(); err == nil {
+		err = request.GetObject(&items)
+	}
+	return
+}
+
+func blueOceanPageSize(pageSize int) int {
+	if pageSize > blueOceanMaxPageSize {
+		return blueOceanMaxPageSize
+	}
+	return pageSize
+}
@@ -0,0 +1,91 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("pager test", func() {
+	var (
+		ctrl         *gomock.Controller
+		c            BlueOceanClient
+		roundTripper *mhttp.MockRoundTripper
+		queries      []string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		c = BlueOceanClient{Organization: "jenkins"}
+		c.RoundTripper = roundTripper
+		c.URL = "http://localhost"
+		queries = nil
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	// given serves the items named from 0 to total-1
+	given := func(path string, total int) {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			Expect(req.URL.Path).To(Equal(path))
+			queries = append(queries, req.URL.RawQuery)
+
+			start, _ := strconv.Atoi(req.URL.Query().Get("start"))
+			limit, err := strconv.Atoi(req.URL.Query().Get("limit"))
+			Expect(err).NotTo(HaveOccurred())
+			var items []string
+			for i := start; i < total && i < start+limit; i++ {
+				items = append(items, fmt.Sprintf(`{"name":"%d"}`, i))
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Request:    req,
+				Body:       io.NopCloser(strings.NewReader("[" + strings.Join(items, ",") + "]")),
+			}, nil
+		}).AnyTimes()
+	}
+
+	It("walk through all the branches", func() {
+		given("/blue/rest/organizations/jenkins/pipelines/folder/pipelines/repo/branches/", 250)
+
+		pager := c.BranchesPager(GetBranchesOption{Folders: []string{"folder"}, PipelineName: "repo"}, 1000)
+		branches, err := pager.Collect(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(branches).To(HaveLen(250))
+		Expect(branches[249].Name).To(Equal("249"))
+		Expect(queries).To(Equal([]string{"limit=100", "limit=100&start=100", "limit=100&start=200"}))
+	})
+
+	It("walk through the runs", func() {
+		given("/blue/rest/organizations/jenkins/pipelines/folder/pipelines/repo/runs/", 3)
+
+		runs, err := c.PipelineRunsPager(2, "repo", "folder").Collect(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(runs).To(HaveLen(3))
+		Expect(queries).To(Equal([]string{"limit=2&start=0", "limit=2&start=2"}))
+	})
+
+	It("stop on errors", func() {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Request:    req,
+				Body:       io.NopCloser(strings.NewReader("")),
+			}, nil
+		})
+
+		_, err := c.PipelinesPager(10).Collect(context.Background())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,6 +1,7 @@
 package job
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/verystar/jenkins-client/pkg/core"
@@ -40,11 +41,21 @@ type JenkinsStatusClient struct {
 	core.JenkinsCore
 }
 
+// NewJenkinsStatusClient creates a JenkinsStatusClient which shares the HTTP client with the JenkinsCore
+func NewJenkinsStatusClient(jenkins *core.JenkinsCore) *JenkinsStatusClient {
+	return &JenkinsStatusClient{JenkinsCore: jenkins.Clone()}
+}
+
 // Get returns status of Jenkins
 func (q *JenkinsStatusClient) Get() (status *JenkinsStatus, err error) {
+	return q.GetContext(context.Background())
+}
+
+// GetContext is the same as Get but accepts a context
+func (q *JenkinsStatusClient) GetContext(ctx context.Context) (status *JenkinsStatus, err error) {
 	status = &JenkinsStatus{}
 	var response *http.Response
-	response, err = q.RequestWithResponseHeader(http.MethodGet, "/api/json", nil, nil, status)
+	response, err = q.RequestWithResponseHeaderContext(ctx, http.MethodGet, "/api/json", nil, nil, status)
 	if err == nil {
 		if ver, ok := response.Header["X-Jenkins"]; ok && len(ver) > 0 {
 			status.Version = ver[0]
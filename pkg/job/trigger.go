@@ -0,0 +1,109 @@
+package job
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/verystar/jenkins-client/pkg/core"
+	"github.com/verystar/jenkins-client/pkg/queue"
+)
+
+// QueueRef is the queue item of a triggered build
+type QueueRef struct {
+	// ID is zero if the URL is not a queue item URL, e.g. it was rewritten by a reverse proxy
+	ID  int
+	URL string
+}
+
+// WaitTarget returns the target of WaitForBuild
+func (r QueueRef) WaitTarget() WaitTarget {
+	return WaitTarget{QueueID: r.ID}
+}
+
+// TriggerOptions is the options of triggering a build
+type TriggerOptions struct {
+	// Delay is the quiet period before the build starts, the one of the job is used if it is zero.
+	// It is rounded up to whole seconds, so a delay less than one second is still a delay.
+	Delay time.Duration
+	// Token is the authentication token of triggering builds remotely
+	Token string
+	// Cause is the description of the build cause, it works together with the Token
+	Cause string
+}
+
+func (o TriggerOptions) query() url.Values {
+	query := url.Values{}
+	if o.Delay > 0 {
+		seconds := (o.Delay + time.Second - 1) / time.Second
+		query.Set("delay", strconv.FormatInt(int64(seconds), 10)+"sec")
+	}
+	if o.Token != "" {
+		query.Set("token", o.Token)
+	}
+	if o.Cause != "" {
+		query.Set("cause", o.Cause)
+	}
+	return query
+}
+
+// BuildWithOptions triggers a job which has no parameters
+func (q *Client) BuildWithOptions(jobName string, options TriggerOptions) (ref QueueRef, err error) {
+	return q.BuildWithOptionsContext(context.Background(), jobName, options)
+}
+
+// BuildWithOptionsContext is the same as BuildWithOptions but accepts a context
+func (q *Client) BuildWithOptionsContext(ctx context.Context, jobName string, options TriggerOptions) (ref QueueRef, err error) {
+	return q.trigger(ctx, ParseJobPath(jobName)+"/build", options.query(), nil, nil)
+}
+
+// BuildWithParameters triggers a job with the values of its parameters via the buildWithParameters API,
+// the default values are used for the missing parameters
+func (q *Client) BuildWithParameters(jobName string, parameters map[string]string, options TriggerOptions) (ref QueueRef, err error) {
+	return q.BuildWithParametersContext(context.Background(), jobName, parameters, options)
+}
+
+// BuildWithParametersContext is the same as BuildWithParameters but accepts a context
+func (q *Client) BuildWithParametersContext(ctx context.Context, jobName string, parameters map[string]string,
+	options TriggerOptions) (ref QueueRef, err error) {
+	formData := url.Values{}
+	for name, value := range parameters {
+		formData.Set(name, value)
+	}
+	return q.trigger(ctx, ParseJobPath(jobName)+"/buildWithParameters", options.query(),
+		map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, strings.NewReader(formData.Encode()))
+}
+
+// trigger sends the request of triggering a build, then returns the queue item from the Location header.
+// The QueueRef is empty if there is no Location header. The build is queued once Jenkins responds 201,
+// so there is no error even if the Location header is not a queue item URL, the ID is zero in that case.
+func (q *Client) trigger(ctx context.Context, api string, query url.Values, headers map[string]string,
+	payload io.Reader) (ref QueueRef, err error) {
+	if len(query) > 0 {
+		api += "?" + query.Encode()
+	}
+
+	var response *http.Response
+	if response, err = q.RequestWithResponseContext(ctx, http.MethodPost, api, headers, payload); err != nil {
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode != http.StatusCreated {
+		data, _ := io.ReadAll(response.Body)
+		err = core.NewAPIError(response, data)
+		return
+	}
+	if ref.URL = response.Header.Get("Location"); ref.URL != "" {
+		if id, parseErr := queue.ParseItemID(ref.URL); parseErr == nil {
+			ref.ID = id
+		}
+	}
+	return
+}
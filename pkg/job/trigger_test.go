@@ -0,0 +1,100 @@
+package job
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/core"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("trigger test", func() {
+	var (
+		ctrl         *gomock.Controller
+		jobClient    Client
+		roundTripper *mhttp.MockRoundTripper
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		jobClient = Client{}
+		jobClient.RoundTripper = roundTripper
+		jobClient.URL = "http://localhost"
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("return the queue item from the Location header", func() {
+		request, _ := http.NewRequest(http.MethodPost, "http://localhost/job/fake/build", nil)
+		response := core.PrepareCommonPostWithResponseCode(request, "", http.StatusCreated, roundTripper, "", "", jobClient.URL)
+		response.Header = http.Header{"Location": {"http://localhost/queue/item/12/"}}
+
+		ref, err := jobClient.Build("fake")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ref).To(Equal(QueueRef{ID: 12, URL: "http://localhost/queue/item/12/"}))
+		Expect(ref.WaitTarget()).To(Equal(WaitTarget{QueueID: 12}))
+	})
+
+	It("with the delay and the token", func() {
+		request, _ := http.NewRequest(http.MethodPost, "http://localhost/job/fake/build?"+url.Values{
+			"delay": {"5sec"}, "token": {"secret"}, "cause": {"from CI"},
+		}.Encode(), nil)
+		response := core.PrepareCommonPostWithResponseCode(request, "", http.StatusCreated, roundTripper, "", "", jobClient.URL)
+		response.Header = http.Header{"Location": {"http://localhost/queue/item/13/"}}
+
+		ref, err := jobClient.BuildWithOptions("fake", TriggerOptions{Delay: 5 * time.Second, Token: "secret", Cause: "from CI"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ref.ID).To(Equal(13))
+	})
+
+	It("keep the Location which is not a queue item URL", func() {
+		request, _ := http.NewRequest(http.MethodPost, "http://localhost/job/fake/build", nil)
+		response := core.PrepareCommonPostWithResponseCode(request, "", http.StatusCreated, roundTripper, "", "", jobClient.URL)
+		response.Header = http.Header{"Location": {"https://proxy.example.com/jenkins/queued"}}
+
+		ref, err := jobClient.Build("fake")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ref).To(Equal(QueueRef{URL: "https://proxy.example.com/jenkins/queued"}))
+	})
+
+	It("round up the delay to whole seconds", func() {
+		Expect(TriggerOptions{Delay: 200 * time.Millisecond}.query().Get("delay")).To(Equal("1sec"))
+		Expect(TriggerOptions{Delay: 1500 * time.Millisecond}.query().Get("delay")).To(Equal("2sec"))
+		Expect(TriggerOptions{Delay: 3 * time.Second}.query().Get("delay")).To(Equal("3sec"))
+		Expect(TriggerOptions{}.query()).NotTo(HaveKey("delay"))
+	})
+
+	It("with the parameters", func() {
+		payload := url.Values{"name": {"value"}}.Encode()
+		request, _ := http.NewRequest(http.MethodPost, "http://localhost/job/fake/buildWithParameters?token=secret",
+			strings.NewReader(payload))
+		request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		response := core.PrepareCommonPostWithResponseCode(request, "", http.StatusCreated, roundTripper, "", "", jobClient.URL)
+		response.Header = http.Header{"Location": {"http://localhost/queue/item/14/"}}
+
+		ref, err := jobClient.BuildWithParameters("fake", map[string]string{"name": "value"}, TriggerOptions{Token: "secret"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ref.ID).To(Equal(14))
+	})
+
+	It("with an error status code", func() {
+		request, _ := http.NewRequest(http.MethodPost, "http://localhost/job/fake/buildWithParameters", strings.NewReader(""))
+		request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		core.PrepareCommonPostWithResponseCode(request, "no such parameter", http.StatusBadRequest, roundTripper, "", "", jobClient.URL)
+
+		_, err := jobClient.BuildWithParameters("fake", nil, TriggerOptions{})
+		var apiError *core.APIError
+		Expect(errors.As(err, &apiError)).To(BeTrue())
+		Expect(apiError.StatusCode).To(Equal(http.StatusBadRequest))
+		Expect(apiError.Body).To(Equal("no such parameter"))
+	})
+})
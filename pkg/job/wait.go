@@ -0,0 +1,121 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/verystar/jenkins-client/pkg/queue"
+)
+
+// DefaultPollInterval is the interval of polling the queue item and the build if there is no one
+const DefaultPollInterval = 2 * time.Second
+
+// WaitTarget is the build to wait for, it can be a queue item or a started build
+type WaitTarget struct {
+	// Location is the Location header of the response of triggering a build, such as http://localhost/queue/item/1/
+	Location string
+	// QueueID is the ID of the queue item, it is ignored if there is a Location
+	QueueID int
+	// BuildNumber is the number of a started build, the queue item is ignored if it is positive
+	BuildNumber int
+}
+
+// WaitOptions is the options of waiting for a build
+type WaitOptions struct {
+	// PollInterval is the interval of polling, DefaultPollInterval is used if it is not positive
+	PollInterval time.Duration
+	// OnQueued is called when the queue item is fetched at the first time or the reason of waiting changes
+	OnQueued func(item *queue.Item)
+	// OnStarted is called when the build is fetched at the first time
+	OnStarted func(build *Build)
+	// OnFinished is called when the build is not building anymore
+	OnFinished func(build *Build)
+}
+
+// WaitForBuild waits until the queue item becomes a build and the build finishes, then returns the final build.
+// The error is queue.ErrItemCancelled if the queue item was cancelled.
+func (q *Client) WaitForBuild(ctx context.Context, jobName string, target WaitTarget, options WaitOptions) (build *Build, err error) {
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultPollInterval
+	}
+
+	number := target.BuildNumber
+	if number <= 0 {
+		if number, err = q.waitForQueueItem(ctx, target, options); err != nil {
+			return
+		}
+	}
+
+	started := false
+	err = poll(ctx, options.PollInterval, func() (done bool, err error) {
+		if build, err = q.GetBuildContext(ctx, jobName, number); err != nil {
+			return
+		}
+		if !started && options.OnStarted != nil {
+			options.OnStarted(build)
+		}
+		started = true
+
+		if done = !build.Building; done && options.OnFinished != nil {
+			options.OnFinished(build)
+		}
+		return
+	})
+	return
+}
+
+// waitForQueueItem returns the build number once the queue item left the queue
+func (q *Client) waitForQueueItem(ctx context.Context, target WaitTarget, options WaitOptions) (number int, err error) {
+	id := target.QueueID
+	if target.Location != "" {
+		if id, err = queue.ParseItemID(target.Location); err != nil {
+			return
+		}
+	}
+	if id <= 0 {
+		err = errors.New("the queue item or the build number is required")
+		return
+	}
+
+	queueClient := &queue.Client{JenkinsCore: q.JenkinsCore}
+	var why *string
+	err = poll(ctx, options.PollInterval, func() (done bool, err error) {
+		var item *queue.Item
+		if item, err = queueClient.GetItemContext(ctx, id); err != nil {
+			return
+		}
+		switch {
+		case item.Cancelled:
+			err = queue.ErrItemCancelled
+		case item.Executable != nil:
+			number, done = item.Executable.Number, true
+		case why == nil || *why != item.Why:
+			why = &item.Why
+			if options.OnQueued != nil {
+				options.OnQueued(item)
+			}
+		}
+		return
+	})
+	return
+}
+
+// poll calls the check function until it is done, or there is an error
+func poll(ctx context.Context, interval time.Duration, check func() (bool, error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if done, err := check(); done || err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
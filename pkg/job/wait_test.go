@@ -0,0 +1,108 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/verystar/jenkins-client/pkg/core"
+	"github.com/verystar/jenkins-client/pkg/mock/mhttp"
+	"github.com/verystar/jenkins-client/pkg/queue"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("wait for build test", func() {
+	var (
+		ctrl         *gomock.Controller
+		jobClient    Client
+		roundTripper *mhttp.MockRoundTripper
+		events       []string
+		options      WaitOptions
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		roundTripper = mhttp.NewMockRoundTripper(ctrl)
+		jobClient = Client{}
+		jobClient.RoundTripper = roundTripper
+		jobClient.URL = "http://localhost"
+		events = nil
+		options = WaitOptions{
+			PollInterval: time.Millisecond,
+			OnQueued: func(item *queue.Item) {
+				events = append(events, "queued: "+item.Why)
+			},
+			OnStarted: func(build *Build) {
+				events = append(events, "started")
+			},
+			OnFinished: func(build *Build) {
+				events = append(events, "finished: "+build.Result)
+			},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	given := func(api string, code int, body string) {
+		request, _ := http.NewRequest(http.MethodGet, jobClient.URL+api, nil)
+		roundTripper.EXPECT().RoundTrip(core.NewRequestMatcher(request)).Return(&http.Response{
+			StatusCode: code,
+			Request:    request,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+		}, nil)
+	}
+
+	It("from the queue item to the finished build", func() {
+		given("/queue/item/12/api/json", http.StatusOK, `{"id":12,"why":"Waiting for next available executor"}`)
+		given("/queue/item/12/api/json", http.StatusOK, `{"id":12,"why":"Waiting for next available executor"}`)
+		given("/queue/item/12/api/json", http.StatusOK, `{"id":12,"why":"","executable":{"number":3}}`)
+		given("/job/fake/3/api/json", http.StatusOK, `{"number":3,"building":true}`)
+		given("/job/fake/3/api/json", http.StatusOK, `{"number":3,"building":false,"result":"SUCCESS"}`)
+
+		build, err := jobClient.WaitForBuild(context.Background(), "fake",
+			WaitTarget{Location: "http://localhost/queue/item/12/"}, options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(build.Number).To(Equal(3))
+		Expect(build.Result).To(Equal("SUCCESS"))
+		Expect(events).To(Equal([]string{"queued: Waiting for next available executor", "started", "finished: SUCCESS"}))
+	})
+
+	It("with a build number", func() {
+		given("/job/fake/3/api/json", http.StatusOK, `{"number":3,"building":false,"result":"FAILURE"}`)
+
+		build, err := jobClient.WaitForBuild(context.Background(), "fake", WaitTarget{BuildNumber: 3}, options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(build.Result).To(Equal("FAILURE"))
+		Expect(events).To(Equal([]string{"started", "finished: FAILURE"}))
+	})
+
+	It("the queue item was cancelled", func() {
+		given("/queue/item/12/api/json", http.StatusOK, `{"id":12,"cancelled":true}`)
+
+		_, err := jobClient.WaitForBuild(context.Background(), "fake", WaitTarget{QueueID: 12}, options)
+		Expect(errors.Is(err, queue.ErrItemCancelled)).To(BeTrue())
+	})
+
+	It("the context is done", func() {
+		given("/job/fake/3/api/json", http.StatusOK, `{"number":3,"building":true}`)
+		ctx, cancel := context.WithCancel(context.Background())
+		options.OnStarted = func(*Build) {
+			cancel()
+		}
+
+		_, err := jobClient.WaitForBuild(ctx, "fake", WaitTarget{BuildNumber: 3}, options)
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+	})
+
+	It("without a target", func() {
+		_, err := jobClient.WaitForBuild(context.Background(), "fake", WaitTarget{}, options)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,28 +1,51 @@
 package queue
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/verystar/jenkins-client/pkg/core"
 )
 
+// ErrItemCancelled means the queue item was cancelled before it started
+var ErrItemCancelled = errors.New("the queue item was cancelled")
+
 // Client is the client of queue
 type Client struct {
 	core.JenkinsCore
 }
 
+// NewClient creates a Client which shares the HTTP client with the JenkinsCore
+func NewClient(jenkins *core.JenkinsCore) *Client {
+	return &Client{JenkinsCore: jenkins.Clone()}
+}
+
 // Get returns the job queue
 func (q *Client) Get() (status *JobQueue, err error) {
-	err = q.RequestWithData(http.MethodGet, "/queue/api/json", nil, nil, 200, &status)
+	return q.GetContext(context.Background())
+}
+
+// GetContext is the same as Get but accepts a context
+func (q *Client) GetContext(ctx context.Context) (status *JobQueue, err error) {
+	err = q.RequestWithDataContext(ctx, http.MethodGet, "/queue/api/json", nil, nil, 200, &status)
 	return
 }
 
 // Cancel will cancel a job from the queue
 func (q *Client) Cancel(id int) (err error) {
+	return q.CancelContext(context.Background(), id)
+}
+
+// CancelContext is the same as Cancel but accepts a context
+func (q *Client) CancelContext(ctx context.Context, id int) (err error) {
 	api := fmt.Sprintf("/queue/cancelItem?id=%d", id)
 	var statusCode int
-	if statusCode, err = q.RequestWithoutData(http.MethodPost, api, nil, nil, 302); err != nil &&
+	if statusCode, err = q.RequestWithoutDataContext(ctx, http.MethodPost, api, nil, nil, 302); err != nil &&
 		(statusCode == 200 ||
 			statusCode == 404) { // 404 should be an error, but no idea why it can be triggered successful
 		err = nil
@@ -30,6 +53,36 @@ func (q *Client) Cancel(id int) (err error) {
 	return
 }
 
+// GetItem returns a queue item, it can be found for a few minutes after it left the queue
+func (q *Client) GetItem(id int) (item *Item, err error) {
+	return q.GetItemContext(context.Background(), id)
+}
+
+// GetItemContext is the same as GetItem but accepts a context
+func (q *Client) GetItemContext(ctx context.Context, id int) (item *Item, err error) {
+	api := fmt.Sprintf("/queue/item/%d/api/json", id)
+	err = q.RequestWithDataContext(ctx, http.MethodGet, api, nil, nil, 200, &item)
+	return
+}
+
+// ParseItemID returns the ID of a queue item from its URL, such as the Location header
+// of the response of triggering a build: http://localhost:8080/queue/item/12/
+func ParseItemID(location string) (id int, err error) {
+	var itemURL *url.URL
+	if itemURL, err = url.Parse(location); err != nil {
+		return
+	}
+	segments := strings.Split(strings.Trim(itemURL.Path, "/"), "/")
+	count := len(segments)
+	if count < 3 || segments[count-3] != "queue" || segments[count-2] != "item" {
+		return 0, fmt.Errorf("%q is not a queue item URL", location)
+	}
+	if id, err = strconv.Atoi(segments[count-1]); err != nil {
+		err = fmt.Errorf("%q is not a queue item URL: %w", location, err)
+	}
+	return
+}
+
 // JobQueue represent the job queue
 type JobQueue struct {
 	Items []Item
@@ -48,6 +101,15 @@ type Item struct {
 	BuildableStartMilliseconds int64
 	InQueueSince               int64
 	Actions                    []CauseAction
+	Cancelled                  bool
+	// Executable is the build of this item, it is nil before the build starts
+	Executable *Executable
+}
+
+// Executable is the build which started from a queue item
+type Executable struct {
+	Number int
+	URL    string
 }
 
 // CauseAction is the collection of causes
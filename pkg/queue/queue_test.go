@@ -1,6 +1,10 @@
 package queue
 
 import (
+	"bytes"
+	"io"
+	"net/http"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/verystar/jenkins-client/pkg/core"
@@ -47,4 +51,34 @@ var _ = Describe("queue test", func() {
 			Expect(err).To(BeNil())
 		})
 	})
+
+	Context("get item", func() {
+		It("should success", func() {
+			request, _ := http.NewRequest(http.MethodGet, "http://localhost/queue/item/12/api/json", nil)
+			roundTripper.EXPECT().RoundTrip(core.NewRequestMatcher(request)).Return(&http.Response{
+				StatusCode: http.StatusOK,
+				Request:    request,
+				Body: io.NopCloser(bytes.NewBufferString(
+					`{"id":12,"why":"","executable":{"number":3,"url":"http://localhost/job/a/3/"}}`)),
+			}, nil)
+
+			item, err := queueClient.GetItem(12)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(item.ID).To(Equal(12))
+			Expect(item.Executable).To(Equal(&Executable{Number: 3, URL: "http://localhost/job/a/3/"}))
+		})
+	})
+
+	DescribeTable("parse item ID",
+		func(location string, id int, ok bool) {
+			result, err := ParseItemID(location)
+			Expect(err == nil).To(Equal(ok))
+			Expect(result).To(Equal(id))
+		},
+		Entry("absolute URL", "http://localhost:8080/queue/item/12/", 12, true),
+		Entry("with a context path", "http://localhost/jenkins/queue/item/3", 3, true),
+		Entry("relative path", "/queue/item/5/", 5, true),
+		Entry("not a queue item", "http://localhost/job/a/", 0, false),
+		Entry("invalid ID", "http://localhost/queue/item/abc/", 0, false),
+	)
 })
@@ -1,6 +1,7 @@
 package user
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -19,6 +20,11 @@ type Client struct {
 	core.JenkinsCore
 }
 
+// NewClient creates a Client which shares the HTTP client with the JenkinsCore
+func NewClient(jenkins *core.JenkinsCore) *Client {
+	return &Client{JenkinsCore: jenkins.Clone()}
+}
+
 // Token is the token of user
 type Token struct {
 	Status string    `json:"status"`
@@ -35,24 +41,39 @@ type TokenData struct {
 
 // Get returns a user's detail
 func (q *Client) Get() (status *User, err error) {
+	return q.GetContext(context.Background())
+}
+
+// GetContext is the same as Get but accepts a context
+func (q *Client) GetContext(ctx context.Context) (status *User, err error) {
 	api := fmt.Sprintf("/user/%s/api/json", q.UserName)
-	err = q.RequestWithData(http.MethodGet, api, nil, nil, 200, &status)
+	err = q.RequestWithDataContext(ctx, http.MethodGet, api, nil, nil, 200, &status)
 	return
 }
 
 // EditDesc update the description of a user
 func (q *Client) EditDesc(description string) (err error) {
+	return q.EditDescContext(context.Background(), description)
+}
+
+// EditDescContext is the same as EditDesc but accepts a context
+func (q *Client) EditDescContext(ctx context.Context, description string) (err error) {
 	formData := url.Values{}
 	formData.Add("description", description)
 	payload := strings.NewReader(formData.Encode())
-	_, err = q.RequestWithoutData(http.MethodPost, fmt.Sprintf("/user/%s/submitDescription", q.UserName),
+	_, err = q.RequestWithoutDataContext(ctx, http.MethodPost, fmt.Sprintf("/user/%s/submitDescription", q.UserName),
 		map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, payload, 200)
 	return
 }
 
 // Delete will remove a user from Jenkins
 func (q *Client) Delete(username string) (err error) {
-	_, err = q.RequestWithoutData(http.MethodPost, fmt.Sprintf("/securityRealm/user/%s/doDelete", username),
+	return q.DeleteContext(context.Background(), username)
+}
+
+// DeleteContext is the same as Delete but accepts a context
+func (q *Client) DeleteContext(ctx context.Context, username string) (err error) {
+	_, err = q.RequestWithoutDataContext(ctx, http.MethodPost, fmt.Sprintf("/securityRealm/user/%s/doDelete", username),
 		map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, nil, 200)
 	return
 }
@@ -81,6 +102,11 @@ func genSimpleUserAsPayload(username, password string) (payload io.Reader, user
 
 // Create will create a user in Jenkins
 func (q *Client) Create(username, password string) (user *ForCreate, err error) {
+	return q.CreateContext(context.Background(), username, password)
+}
+
+// CreateContext is the same as Create but accepts a context
+func (q *Client) CreateContext(ctx context.Context, username, password string) (user *ForCreate, err error) {
 	var (
 		payload io.Reader
 		code    int
@@ -91,7 +117,7 @@ func (q *Client) Create(username, password string) (user *ForCreate, err error)
 	}
 
 	payload, user = genSimpleUserAsPayload(username, password)
-	code, err = q.RequestWithoutData(http.MethodPost, "/securityRealm/createAccountByAdmin",
+	code, err = q.RequestWithoutDataContext(ctx, http.MethodPost, "/securityRealm/createAccountByAdmin",
 		map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, payload, 200)
 	if code == 302 {
 		err = nil
@@ -101,6 +127,11 @@ func (q *Client) Create(username, password string) (user *ForCreate, err error)
 
 // CreateToken create a token in Jenkins
 func (q *Client) CreateToken(targetUser, newTokenName string) (status *Token, err error) {
+	return q.CreateTokenContext(context.Background(), targetUser, newTokenName)
+}
+
+// CreateTokenContext is the same as CreateToken but accepts a context
+func (q *Client) CreateTokenContext(ctx context.Context, targetUser, newTokenName string) (status *Token, err error) {
 	if newTokenName == "" {
 		newTokenName = fmt.Sprintf("jcli-%s", time.Now().Format("20060102"))
 	}
@@ -115,7 +146,7 @@ func (q *Client) CreateToken(targetUser, newTokenName string) (status *Token, er
 	formData.Add("newTokenName", newTokenName)
 	payload := strings.NewReader(formData.Encode())
 
-	err = q.RequestWithData(http.MethodPost, api,
+	err = q.RequestWithDataContext(ctx, http.MethodPost, api,
 		map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, payload, 200, &status)
 	return
 }